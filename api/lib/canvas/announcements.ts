@@ -0,0 +1,16 @@
+import { AnnouncementCreateAttributes, Client } from "./types";
+
+export const announcementsApi = (client: Client) => ({
+    getAll: (courseId: string) => client.request('get', `courses/${courseId}/discussion_topics?only_announcements=true`),
+    create: (courseId: string, data: AnnouncementCreateAttributes) => {
+        const formData = new URLSearchParams();
+        formData.append('title', data.title);
+        formData.append('message', data.message);
+        formData.append('is_announcement', 'true');
+        formData.append('published', 'true');
+        if (data.delayed_post_at) {
+            formData.append('delayed_post_at', data.delayed_post_at.toISOString());
+        }
+        return client.request('post', `courses/${courseId}/discussion_topics`, formData);
+    },
+});
@@ -1,13 +1,33 @@
-import { AssignmentUpdatedAttributes, Client } from "./types";
+import { AssignmentCreateAttributes, AssignmentGroupUpdatedAttributes, AssignmentOverrideAttributes, AssignmentUpdatedAttributes, Client } from "./types";
 import { objectToFormData } from "./helpers";
 
 export const assignmentsApi = (client: Client) => ({
     getAll: (courseId: string) => client.request('get', `courses/${courseId}/assignments`),
     getForUser: (courseId: string, userId: string) => client.request('get', `users/${userId}/courses/${courseId}/assignments`),
+    missingForUser: (courseId: string, userId: string) => client.request('get', `users/${userId}/missing_submissions?course_ids[]=${courseId}&filter[]=submittable`),
     get: (assignmentId: number, courseId: string) => client.request('get', `courses/${courseId}/assignments/${assignmentId}`),
+    create: (courseId: string, data: AssignmentCreateAttributes) => {
+        const formData = objectToFormData(data, 'assignment');
+        return client.request('post', `courses/${courseId}/assignments`, formData);
+    },
     update: (assignmentId: number, courseId: string, data: AssignmentUpdatedAttributes) => {
         const formData = objectToFormData(data, 'assignment');
         return client.request('put', `courses/${courseId}/assignments/${assignmentId}`, formData);
     },
-    groups: (courseId: string) => client.request('get', `/courses/${courseId}/assignment_groups`),
+    overrides: (assignmentId: number, courseId: string) => client.request('get', `courses/${courseId}/assignments/${assignmentId}/overrides`),
+    createOverride: (assignmentId: number, courseId: string, data: AssignmentOverrideAttributes) => {
+        const formData = objectToFormData(data, 'assignment_override');
+        return client.request('post', `courses/${courseId}/assignments/${assignmentId}/overrides`, formData);
+    },
+    updateOverride: (assignmentId: number, courseId: string, overrideId: number, data: AssignmentOverrideAttributes) => {
+        const formData = objectToFormData(data, 'assignment_override');
+        return client.request('put', `courses/${courseId}/assignments/${assignmentId}/overrides/${overrideId}`, formData);
+    },
+    deleteOverride: (assignmentId: number, courseId: string, overrideId: number) => client.request('delete', `courses/${courseId}/assignments/${assignmentId}/overrides/${overrideId}`),
+    groups: (courseId: string, include: string[] = []) => client.request('get', `/courses/${courseId}/assignment_groups?${include.map(value => `include[]=${value}`).join('&')}`),
+    updateGroup: (groupId: number, courseId: string, data: AssignmentGroupUpdatedAttributes) => {
+        const formData = new URLSearchParams();
+        Object.entries(data).forEach(([key, value]) => formData.append(key, String(value)));
+        return client.request('put', `/courses/${courseId}/assignment_groups/${groupId}`, formData);
+    },
 });
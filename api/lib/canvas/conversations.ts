@@ -0,0 +1,16 @@
+import { Client, ConversationCreateAttributes } from "./types";
+
+export const conversationsApi = (client: Client) => ({
+    create: (data: ConversationCreateAttributes) => {
+        const formData = new URLSearchParams();
+        data.recipients.forEach(recipient => formData.append('recipients[]', String(recipient)));
+        formData.append('subject', data.subject);
+        formData.append('body', data.body);
+        formData.append('force_new', String(data.force_new ?? true));
+        formData.append('group_conversation', String(data.group_conversation ?? false));
+        if (data.context_code) {
+            formData.append('context_code', data.context_code);
+        }
+        return client.request('post', 'conversations', formData);
+    },
+});
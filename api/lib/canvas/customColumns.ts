@@ -0,0 +1,6 @@
+import { Client } from "./types";
+
+export const customColumnsApi = (client: Client) => ({
+    getAll: (courseId: string) => client.request('get', `courses/${courseId}/custom_gradebook_columns?include_hidden=true`),
+    data: (courseId: string, columnId: number) => client.request('get', `courses/${courseId}/custom_gradebook_columns/${columnId}/data`),
+});
@@ -1,8 +1,12 @@
 import { accessTokenApi } from "./accessToken";
+import { announcementsApi } from "./announcements";
 import { assignmentsApi } from "./assignments";
+import { conversationsApi } from "./conversations";
 import { coursesApi } from "./courses";
+import { customColumnsApi } from "./customColumns";
 import { filesApi } from "./files";
 import { modulesApi } from "./modules";
+import { pagesApi } from "./pages";
 import { submissionsApi } from "./submissions";
 import { request } from './helpers';
 import { CanvasApi, CanvasConfig, Client } from "./types";
@@ -18,12 +22,16 @@ export const createClient = (config: CanvasConfig): Client => {
 export const createCanvasApi = (client: Client): CanvasApi => {
   return {
     accessTokenApi: accessTokenApi(client),
+    announcements: announcementsApi(client),
     assignments: assignmentsApi(client),
     client: client,
+    conversations: conversationsApi(client),
     courses: coursesApi(client),
+    customColumns: customColumnsApi(client),
     enrollments: enrollmentsApi(client),
     files: filesApi(client),
     modules: modulesApi(client),
+    pages: pagesApi(client),
     request: request.bind(client),
     submissions: submissionsApi(client),
   };
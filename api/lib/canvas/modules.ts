@@ -1,15 +1,31 @@
-import { Client, ModuleUpdateAttributes, ModuleItemUpdateAttributes } from "./types";
+import { Client, ModuleUpdateAttributes, ModuleItemCreateAttributes, ModuleItemUpdateAttributes } from "./types";
 import { objectToFormData } from "./helpers";
 
 export const modulesApi = (client: Client) => ({
     getAll: (courseId: string) => client.request('get', `courses/${courseId}/modules`),
     get: (courseId: string, moduleId: string) => client.request('get', `courses/${courseId}modules/${moduleId}`),
     update: (courseId: string, moduleId: string, data: ModuleUpdateAttributes) => {
-        const formData = objectToFormData(data, 'module');
+        const { unlock_at, prerequisite_module_ids, ...rest } = data;
+        const formData = objectToFormData(rest, 'module');
+        // Canvas clears these when sent empty, which objectToFormData can't express
+        if (unlock_at !== undefined) {
+            formData.append('module[unlock_at]', unlock_at ? unlock_at.toISOString() : '');
+        }
+        if (prerequisite_module_ids !== undefined) {
+            if (prerequisite_module_ids.length) {
+                prerequisite_module_ids.forEach(id => formData.append('module[prerequisite_module_ids][]', id.toString()));
+            } else {
+                formData.append('module[prerequisite_module_ids]', '');
+            }
+        }
         return client.request('put', `courses/${courseId}/modules/${moduleId}`, formData);
     },
-    items: (courseId: string, moduleId: string) => client.request('get', `courses/${courseId}/modules/${moduleId}/items`),
+    items: (courseId: string, moduleId: string) => client.request('get', `courses/${courseId}/modules/${moduleId}/items?include[]=content_details`),
     item: (courseId: string, moduleId: string, itemId: string) => client.request('get', `courses/${courseId}/modules/${moduleId}/items/${itemId}`),
+    createItem: (courseId: string, moduleId: string, data: ModuleItemCreateAttributes) => {
+        const formData = objectToFormData(data, 'module_item');
+        return client.request('post', `courses/${courseId}/modules/${moduleId}/items`, formData);
+    },
     updateItem: (courseId: string, moduleId: string, itemId: string, data: ModuleItemUpdateAttributes) => {
         const formData = objectToFormData(data, 'module_item');
         return client.request('put', `courses/${courseId}/modules/${moduleId}/items/${itemId}`, formData);
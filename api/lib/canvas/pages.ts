@@ -0,0 +1,15 @@
+import { Client, PageCreateAttributes, PageUpdateAttributes } from "./types";
+import { objectToFormData } from "./helpers";
+
+export const pagesApi = (client: Client) => ({
+    getAll: (courseId: string) => client.request('get', `courses/${courseId}/pages`),
+    get: (courseId: string, pageUrl: string) => client.request('get', `courses/${courseId}/pages/${pageUrl}`),
+    create: (courseId: string, data: PageCreateAttributes) => {
+        const formData = objectToFormData(data, 'wiki_page');
+        return client.request('post', `courses/${courseId}/pages`, formData);
+    },
+    update: (courseId: string, pageUrl: string, data: PageUpdateAttributes) => {
+        const formData = objectToFormData(data, 'wiki_page');
+        return client.request('put', `courses/${courseId}/pages/${pageUrl}`, formData);
+    },
+});
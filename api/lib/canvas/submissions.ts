@@ -1,6 +1,27 @@
-import { Client, Submission } from "./types";
+import { Client, GradeUpdate, Submission, SubmissionGradeAttributes, SubmissionCommentAttributes } from "./types";
+import { objectToFormData } from "./helpers";
+
+const includeParams = (include: string[]) => {
+    const params = new URLSearchParams();
+    include.forEach(value => params.append('include[]', value));
+    return params.toString();
+};
 
 export const submissionsApi = (client: Client) => ({
-    getAssignmentSubmissions: (assignmentId: number, courseId: number) => client.request('get', `/courses/${courseId}/assignments/${assignmentId}/submissions`),
+    getAssignmentSubmissions: (assignmentId: number, courseId: number, include: string[] = []) => client.request('get', `/courses/${courseId}/assignments/${assignmentId}/submissions?${includeParams(include)}`),
     get: (assignmentId: number, courseId: number, userId: number) => client.request('get', `/courses/${courseId}/assignments/${assignmentId}/submissions/${userId}`),
+    grade: (assignmentId: number, courseId: number, userId: number, submission: SubmissionGradeAttributes, comment?: SubmissionCommentAttributes) => {
+        const formData = objectToFormData(submission, 'submission');
+        if (comment) {
+            objectToFormData(comment, 'comment').forEach((value, key) => formData.append(key, value));
+        }
+        return client.request('put', `/courses/${courseId}/assignments/${assignmentId}/submissions/${userId}`, formData);
+    },
+    updateGrades: (courseId: number, grades: GradeUpdate[]) => {
+        const formData = new URLSearchParams();
+        grades.forEach(({ assignment_id, user_id, posted_grade }) => {
+            formData.append(`grade_data[${assignment_id}][${user_id}][posted_grade]`, String(posted_grade));
+        });
+        return client.request('post', `/courses/${courseId}/submissions/update_grades`, formData);
+    },
 });
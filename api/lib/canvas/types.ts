@@ -7,12 +7,16 @@ export interface Client {
 
 export interface CanvasApi {
     accessTokenApi: AccessTokenApi;
+    announcements: AnnouncementsApi;
     assignments: AssignmentsApi;
     client: Client;
+    conversations: ConversationsApi;
     courses: CoursesApi;
+    customColumns: CustomColumnsApi;
     enrollments: EnrollmentsApi;
     files: FilesApi;
     modules: ModulesApi;
+    pages: PagesApi;
     request: (method: ValidHTTPMethod, url: string, body?: any) => Promise<any>;
     submissions: SubmissionsApi;
 }
@@ -136,12 +140,69 @@ export interface AccessTokenApi {
     revoke: () => Promise<any>;
 }
 
+export interface AnnouncementsApi {
+    getAll: (courseId: string) => Promise<Announcement[]>;
+    create: (courseId: string, data: AnnouncementCreateAttributes) => Promise<Announcement>;
+}
+
+export interface AssignmentGroupRules {
+    drop_lowest?: number;
+    drop_highest?: number;
+    never_drop?: number[];
+}
+
+export interface AssignmentGroup {
+    id: number;
+    name: string;
+    position: number;
+    group_weight: number;
+    rules: AssignmentGroupRules;
+    assignments?: Assignment[];
+}
+
+export interface AssignmentOverride {
+    id: number;
+    assignment_id: number;
+    title: string;
+    student_ids?: number[];
+    course_section_id?: number;
+    group_id?: number;
+    due_at?: Date | null;
+    unlock_at?: Date | null;
+    lock_at?: Date | null;
+    all_day?: boolean;
+}
+
+export interface AssignmentOverrideAttributes {
+    student_ids?: number[];
+    title?: string;
+    due_at?: Date;
+    unlock_at?: Date;
+    lock_at?: Date;
+}
+
+export interface AssignmentGroupUpdatedAttributes {
+    name?: string;
+    group_weight?: number;
+}
+
 export interface AssignmentsApi {
     getAll: (courseId: string) => Promise<Assignment[]>;
     getForUser: (courseId: string, userId: string) => Promise<Assignment[]>;
+    missingForUser: (courseId: string, userId: string) => Promise<Assignment[]>;
     get: (assignmentId: number, courseId: string) => Promise<Assignment>;
+    create: (courseId: string, data: AssignmentCreateAttributes) => Promise<Assignment>;
     update: (assignmentId: number, courseId: string, data: AssignmentUpdatedAttributes) => Promise<Assignment>;
-    groups: (courseId: string) => Promise<any>
+    overrides: (assignmentId: number, courseId: string) => Promise<AssignmentOverride[]>;
+    createOverride: (assignmentId: number, courseId: string, data: AssignmentOverrideAttributes) => Promise<AssignmentOverride>;
+    updateOverride: (assignmentId: number, courseId: string, overrideId: number, data: AssignmentOverrideAttributes) => Promise<AssignmentOverride>;
+    deleteOverride: (assignmentId: number, courseId: string, overrideId: number) => Promise<AssignmentOverride>;
+    groups: (courseId: string, include?: string[]) => Promise<AssignmentGroup[]>
+    updateGroup: (groupId: number, courseId: string, data: AssignmentGroupUpdatedAttributes) => Promise<AssignmentGroup>
+}
+
+export interface ConversationsApi {
+    create: (data: ConversationCreateAttributes) => Promise<Conversation[]>;
 }
 
 export interface CoursesApi {
@@ -248,12 +309,27 @@ export interface ModulesApi {
     update: (courseId: string, moduleId: string, data: ModuleUpdateAttributes) => Promise<Module>;
     items: (courseId: string, moduleId: string) => Promise<ModuleItem[]>;
     item: (courseId: string, moduleId: string, itemId: string) => Promise<ModuleItem>;
-    updateItem: (courseId: string, moduleId: string, itemId: string, data: ModuleUpdateAttributes) => Promise<ModuleItem>;
+    createItem: (courseId: string, moduleId: string, data: ModuleItemCreateAttributes) => Promise<ModuleItem>;
+    updateItem: (courseId: string, moduleId: string, itemId: string, data: ModuleItemUpdateAttributes) => Promise<ModuleItem>;
+}
+
+export interface CustomColumnsApi {
+    getAll: (courseId: string) => Promise<CustomColumn[]>;
+    data: (courseId: string, columnId: number) => Promise<CustomColumnDatum[]>;
+}
+
+export interface PagesApi {
+    getAll: (courseId: string) => Promise<Page[]>;
+    get: (courseId: string, pageUrl: string) => Promise<Page>;
+    create: (courseId: string, data: PageCreateAttributes) => Promise<Page>;
+    update: (courseId: string, pageUrl: string, data: PageUpdateAttributes) => Promise<Page>;
 }
 
 export interface SubmissionsApi {
-    getAssignmentSubmissions: (assignmentId: number, courseId: number) => Promise<Submission[]>;
+    getAssignmentSubmissions: (assignmentId: number, courseId: number, include?: string[]) => Promise<Submission[]>;
     get: (assignmentId: number, courseId: number, userId: number) => Promise<Submission>;
+    grade: (assignmentId: number, courseId: number, userId: number, submission: SubmissionGradeAttributes, comment?: SubmissionCommentAttributes) => Promise<Submission>;
+    updateGrades: (courseId: number, grades: GradeUpdate[]) => Promise<Progress>;
 }
 
 export interface File {
@@ -352,6 +428,7 @@ export interface ModuleItem {
 }
 
 export interface Submission {
+    id: number;
     assignment_id: number;
     assignment?: any | null; // Use a union type to represent the possibility of it being null or an actual assignment object
     course?: any | null; // Use a union type to represent the possibility of it being null or an actual course object
@@ -377,7 +454,7 @@ export interface Submission {
     late_policy_status: 'late' | 'missing' | 'extended' | 'none' | null;
     points_deducted: number;
     seconds_late: number;
-    workflow_state: 'submitted' | 'graded'; // Use a union type to represent the possible values of the workflow state
+    workflow_state: 'submitted' | 'unsubmitted' | 'graded' | 'pending_review'; // Use a union type to represent the possible values of the workflow state
     extra_attempts: number;
     anonymous_id?: string | null; // Use a union type to represent the possibility of it being null or an actual string
     posted_at?: Date | null; // Use a union type to represent the possibility of it being null or an actual date
@@ -434,10 +511,136 @@ export interface AssignmentUpdatedAttributes {
     force_updated_at?: boolean;
 }
 
+export interface AssignmentCreateAttributes {
+    name: string;
+    description?: string;
+    points_possible?: number;
+    submission_types?: string[];
+    published?: boolean;
+}
+
+export interface SubmissionGradeAttributes {
+    posted_grade?: string;
+    excuse?: boolean;
+    late_policy_status?: 'late' | 'missing' | 'extended' | 'none';
+    seconds_late_override?: number;
+}
+
+export interface GradeUpdate {
+    assignment_id: number;
+    user_id: number;
+    posted_grade: number | string;
+}
+
+export interface Progress {
+    id: number;
+    context_id: number;
+    context_type: string;
+    tag: string;
+    completion: number | null;
+    workflow_state: 'queued' | 'running' | 'completed' | 'failed';
+    message: string | null;
+    url: string;
+}
+
+export interface SubmissionCommentAttributes {
+    text_comment?: string;
+    group_comment?: boolean;
+}
+
+export interface AnnouncementCreateAttributes {
+    title: string;
+    message: string;
+    delayed_post_at?: Date | null;
+}
+
+export interface Announcement {
+    id: number;
+    title: string;
+    message: string;
+    html_url: string;
+    posted_at?: Date | null;
+    delayed_post_at?: Date | null;
+    published: boolean;
+}
+
+export interface ConversationCreateAttributes {
+    recipients: (number | string)[];
+    subject: string;
+    body: string;
+    force_new?: boolean;
+    group_conversation?: boolean;
+    context_code?: string;
+}
+
+export interface Conversation {
+    id: number;
+    subject: string;
+    workflow_state: 'read' | 'unread' | 'archived';
+    last_message: string;
+    last_message_at: Date;
+    message_count: number;
+    participants: { id: number; name: string }[];
+    context_name: string;
+}
+
+export interface Page {
+    page_id: number;
+    url: string;
+    title: string;
+    body?: string | null;
+    created_at: Date;
+    updated_at: Date;
+    published: boolean;
+    front_page: boolean;
+    html_url: string;
+    editing_roles: string;
+    locked_for_user: boolean;
+}
+
+export interface CustomColumn {
+    id: number;
+    title: string;
+    position: number;
+    hidden: boolean;
+    read_only: boolean;
+}
+
+export interface CustomColumnDatum {
+    content: string;
+    user_id: number;
+}
+
+export interface PageCreateAttributes {
+    title: string;
+    body?: string;
+    published?: boolean;
+}
+
+export interface PageUpdateAttributes {
+    title?: string;
+    body?: string;
+    published?: boolean;
+}
+
 export interface ModuleUpdateAttributes {
     name?: string;
     position?: number;
     published?: boolean;
+    unlock_at?: Date | null;
+    require_sequential_progress?: boolean;
+    prerequisite_module_ids?: number[];
+}
+
+export interface ModuleItemCreateAttributes {
+    type: ModuleItem['type'];
+    title?: string;
+    position?: number;
+    indent?: number;
+    content_id?: number;
+    page_url?: string;
+    external_url?: string;
+    new_tab?: boolean;
 }
 
 export interface ModuleItemUpdateAttributes {
@@ -8,6 +8,7 @@ import canvasClient from './config/canvas';
 import { getCourseByName } from './util';
 import ModuleTree from './services/module/ModuleTree';
 import Github from './lib/github';
+import { AssignmentOverrideAttributes, AssignmentUpdatedAttributes, ModuleItem, ModuleItemCreateAttributes, ModuleItemUpdateAttributes, ModuleUpdateAttributes } from './lib/canvas/types';
 
 declare global {
   namespace Express {
@@ -21,6 +22,8 @@ dotenv.config();
 
 const app = express();
 const port = process.env.PORT || 3000;
+// cohort teams live in this organization and are named after the course
+const githubOrganization = 'fullstackacademy';
 
 // Middleware
 app.use(cors());
@@ -54,6 +57,34 @@ app.get('/course/:courseId/modules', async (req: Request, res: Response) => {
   res.json({ status: 'ok', data: modules });
 });
 
+app.post('/course/:courseId/modules/:moduleId', async (req: Request, res: Response) => {
+  logger.info('Updating module settings', { course: req.canvas.client.config.course.name, params: req.params, body: req.body });
+  const { courseId, moduleId } = req.params;
+  const { unlockAt, prerequisiteModuleIds, requireSequentialProgress, published } = req.body;
+
+  const data: ModuleUpdateAttributes = {};
+  if (unlockAt !== undefined) {
+    data.unlock_at = unlockAt ? new Date(unlockAt) : null;
+  }
+  if (Array.isArray(prerequisiteModuleIds)) {
+    data.prerequisite_module_ids = prerequisiteModuleIds;
+  }
+  if (typeof requireSequentialProgress === 'boolean') {
+    data.require_sequential_progress = requireSequentialProgress;
+  }
+  if (typeof published === 'boolean') {
+    data.published = published;
+  }
+
+  if (!Object.keys(data).length) {
+    logger.error('Attempted to update module without any fields');
+    return res.status(400).json({ status: 'error', message: 'Nothing to update' });
+  }
+
+  const module = await req.canvas.modules.update(courseId, moduleId, data);
+  return res.json({ status: 'ok', data: module });
+});
+
 app.get('/course/:courseId/modules/:moduleId', async (req: Request, res: Response) => {
   logger.info('Getting module', { course: req.canvas.client.config.course.name, module: req.params.name });
   const { courseId, moduleId } = req.params;
@@ -62,6 +93,128 @@ app.get('/course/:courseId/modules/:moduleId', async (req: Request, res: Respons
   return res.json({ status: 'ok', data: tree.json() });
 });
 
+app.post('/course/:courseId/modules/:moduleId/layout', async (req: Request, res: Response) => {
+  logger.info('Updating module layout', { course: req.canvas.client.config.course.name, params: req.params, body: req.body });
+  const { courseId, moduleId } = req.params;
+  const { items = [], moves = [] } = req.body as {
+    items: { id: number, indent: number }[],
+    moves: { id: number, moduleId: number }[],
+  };
+
+  const moduleItems: ModuleItem[] = await req.canvas.modules.items(courseId, moduleId);
+  const known = new Map(moduleItems.map(item => [item.id, item]));
+  const unknown = [...items, ...moves].filter(i => !known.has(i.id));
+  if (unknown.length) {
+    logger.error('Attempted to arrange items from another module', { ids: unknown.map(i => i.id) });
+    return res.status(400).json({ status: 'error', message: `Items not in module: ${unknown.map(i => i.id).join(', ')}` });
+  }
+  if (items.some(i => !Number.isInteger(i.indent) || i.indent < 0 || i.indent > 5)) {
+    logger.error('Attempted to set invalid indent', { items });
+    return res.status(400).json({ status: 'error', message: 'Indent must be between 0 and 5' });
+  }
+
+  for (const move of moves) {
+    logger.info('Moving item to module', { item: move.id, module: move.moduleId });
+    await req.canvas.modules.updateItem(courseId, moduleId, String(move.id), { module_id: String(move.moduleId) });
+  }
+
+  // Items the request leaves out sit before the first lesson, where the tree
+  // doesn't show them, so they stay at the top.
+  const moved = new Set(moves.map(m => m.id));
+  const listed = new Set(items.map(i => i.id));
+  const order = moduleItems
+    .filter(item => !moved.has(item.id))
+    .sort((a, b) => a.position - b.position)
+    .map(item => item.id);
+  const layout = [
+    ...order.filter(id => !listed.has(id)).map(id => ({ id, indent: known.get(id)!.indent })),
+    ...items,
+  ];
+
+  // Canvas shifts the other items whenever one changes position, so follow
+  // along and only send the items that aren't already in place.
+  for (let i = 0; i < layout.length; ++i) {
+    const { id, indent } = layout[i];
+    const current = order.indexOf(id);
+    const update: ModuleItemUpdateAttributes = {};
+    if (current !== i) {
+      order.splice(current, 1);
+      order.splice(i, 0, id);
+      update.position = i + 1;
+    }
+    if (known.get(id)!.indent !== indent) {
+      update.indent = indent;
+    }
+    if (Object.keys(update).length) {
+      await req.canvas.modules.updateItem(courseId, moduleId, String(id), update);
+    }
+  }
+
+  const tree = new ModuleTree(await req.canvas.modules.items(courseId, moduleId));
+  return res.json({ status: 'ok', data: tree.json() });
+});
+
+app.post('/course/:courseId/modules/:moduleId/items', async (req: Request, res: Response) => {
+  logger.info('Creating module items', { course: req.canvas.client.config.course.name, params: req.params, body: req.body });
+  const { courseId, moduleId } = req.params;
+  const { position, items = [] } = req.body as {
+    position?: number,
+    items: { type: string, title: string, indent?: number, body?: string, description?: string, points?: number, submissionTypes?: string[], url?: string, newTab?: boolean }[],
+  };
+
+  const types = ['SubHeader', 'Page', 'Assignment', 'ExternalUrl'];
+  const invalid = items.find(item => !types.includes(item.type) || !item.title || (item.type === 'ExternalUrl' && !item.url));
+  if (!items.length || invalid) {
+    logger.error('Attempted to create invalid module items', { invalid });
+    return res.status(400).json({ status: 'error', message: invalid ? `Invalid item: ${invalid.title || invalid.type}` : 'No items to create' });
+  }
+  if (position !== undefined && (!Number.isInteger(position) || position < 1)) {
+    logger.error('Attempted to create module items at invalid position', { position });
+    return res.status(400).json({ status: 'error', message: 'Position must be 1 or more' });
+  }
+
+  // Create in order, each right after the last, so the lesson comes out the
+  // way the template lists it.
+  for (let i = 0; i < items.length; ++i) {
+    const item = items[i];
+    const data: ModuleItemCreateAttributes = {
+      type: item.type as ModuleItem['type'],
+      title: item.title,
+      indent: item.indent ?? (i === 0 ? 0 : 1),
+    };
+    if (position !== undefined) {
+      data.position = position + i;
+    }
+
+    switch (item.type) {
+      case 'Page':
+        const page = await req.canvas.pages.create(courseId, { title: item.title, body: item.body ?? '', published: false });
+        data.page_url = page.url;
+        break;
+      case 'Assignment':
+        const assignment = await req.canvas.assignments.create(courseId, {
+          name: item.title,
+          description: item.description ?? '',
+          points_possible: item.points ?? 0,
+          submission_types: item.submissionTypes ?? ['online_url'],
+          published: false,
+        });
+        data.content_id = assignment.id;
+        break;
+      case 'ExternalUrl':
+        data.external_url = item.url;
+        data.new_tab = item.newTab ?? true;
+        break;
+    }
+
+    logger.info('Creating module item', { item: data });
+    await req.canvas.modules.createItem(courseId, moduleId, data);
+  }
+
+  const tree = new ModuleTree(await req.canvas.modules.items(courseId, moduleId));
+  return res.json({ status: 'ok', data: tree.json() });
+});
+
 app.post('/course/:courseId/modules/:moduleId/lesson', async (req: Request, res: Response) => {
   logger.info('Updating module', { course: req.canvas.client.config.course.name, module: req.params.name, body: req.body });
   const { courseId, moduleId } = req.params;
@@ -132,7 +285,7 @@ app.post('/course/:courseId/modules/:moduleId/lesson', async (req: Request, res:
             logger.error('Failed to find repository', { error: ghRepo.message, repository });
             continue;
           }
-          const addTeamResult = await github.addTeamToRepository('fullstackacademy', req.canvas.client.config.course.name, owner, ghRepo.repository.name);
+          const addTeamResult = await github.addTeamToRepository(githubOrganization, req.canvas.client.config.course.name, owner, ghRepo.repository.name);
           if (!addTeamResult.success) {
             logger.error('Failed to add GitHub Repository', { error: addTeamResult.message, repository });
             continue;
@@ -187,6 +340,292 @@ app.get('/course/:courseId/enrollments', async (req: Request, res: Response) =>
   return res.json({ status: 'ok', data: enrollments });
 });
 
+app.get('/course/:courseId/submissions', async (req: Request, res: Response) => {
+  logger.info('Getting submissions', { course: req.canvas.client.config.course.name, query: req.query });
+  const { courseId } = req.params;
+  const workflowState = typeof req.query.workflow_state === 'string' ? req.query.workflow_state.split(',') : [];
+  const ungradedOnly = workflowState.length > 0 && workflowState.every(s => s === 'submitted' || s === 'pending_review');
+  const assignments = await req.canvas.assignments.getAll(courseId);
+
+  const submissions = [];
+  for (let i = 0; i < assignments.length; ++i) {
+    const assignment = assignments[i];
+    // nothing to grade on this assignment, skip the extra request
+    if (ungradedOnly && !assignment.needs_grading_count) {
+      continue;
+    }
+    const assignmentSubmissions = await req.canvas.submissions.getAssignmentSubmissions(assignment.id, Number(courseId), ['user', 'assignment']);
+    submissions.push(...assignmentSubmissions.filter(s => !workflowState.length || workflowState.includes(s.workflow_state)));
+  }
+
+  return res.json({ status: 'ok', data: submissions });
+});
+
+app.get('/course/:courseId/assignments', async (req: Request, res: Response) => {
+  logger.info('Getting assignments', { course: req.canvas.client.config.course.name });
+  const { courseId } = req.params;
+  const assignments = await req.canvas.assignments.getAll(courseId);
+  return res.json({ status: 'ok', data: assignments });
+});
+
+app.get('/course/:courseId/assignment-groups', async (req: Request, res: Response) => {
+  logger.info('Getting assignment groups', { course: req.canvas.client.config.course.name });
+  const { courseId } = req.params;
+  const course = await req.canvas.courses.get(courseId);
+  const groups = await req.canvas.assignments.groups(courseId, ['assignments']);
+  return res.json({ status: 'ok', data: { weighted: course.apply_assignment_group_weights, groups } });
+});
+
+app.post('/course/:courseId/assignment-groups/:groupId', async (req: Request, res: Response) => {
+  logger.info('Updating assignment group', { course: req.canvas.client.config.course.name, params: req.params, body: req.body });
+  const { courseId, groupId } = req.params;
+  const { weight } = req.body;
+
+  if (typeof weight !== 'number' || weight < 0) {
+    logger.error('Invalid assignment group weight', { weight });
+    return res.status(400).json({ status: 'error', message: 'Weight must be a non-negative number' });
+  }
+
+  const group = await req.canvas.assignments.updateGroup(Number(groupId), courseId, { group_weight: weight });
+  return res.json({ status: 'ok', data: group });
+});
+
+app.get('/course/:courseId/overrides', async (req: Request, res: Response) => {
+  logger.info('Getting assignment overrides', { course: req.canvas.client.config.course.name });
+  const { courseId } = req.params;
+  const assignments = await req.canvas.assignments.getAll(courseId);
+
+  const overrides = [];
+  for (const assignment of assignments.filter(a => a.has_overrides)) {
+    const assignmentOverrides = await req.canvas.assignments.overrides(assignment.id, courseId);
+    overrides.push(...assignmentOverrides.map(o => ({ ...o, assignment_name: assignment.name, assignment_due_at: assignment.due_at })));
+  }
+  return res.json({ status: 'ok', data: overrides });
+});
+
+app.post('/course/:courseId/assignments/:assignmentId/overrides', async (req: Request, res: Response) => {
+  logger.info('Creating assignment override', { course: req.canvas.client.config.course.name, params: req.params, body: req.body });
+  const { courseId, assignmentId } = req.params;
+  const { studentIds = [], title, dueAt, lockAt } = req.body;
+
+  if (!studentIds.length || !dueAt) {
+    logger.error('Attempted to create override without students or due date');
+    return res.status(400).json({ status: 'error', message: 'Students and a due date are required' });
+  }
+
+  const data: AssignmentOverrideAttributes = { student_ids: studentIds, due_at: new Date(dueAt) };
+  if (title) {
+    data.title = title;
+  }
+  if (lockAt) {
+    data.lock_at = new Date(lockAt);
+  }
+
+  // Canvas allows one student override per student and assignment, so an
+  // existing extension for the same students is moved instead of duplicated
+  const existing = await req.canvas.assignments.overrides(Number(assignmentId), courseId);
+  const sameStudents = existing.find(o => o.student_ids?.length === studentIds.length && studentIds.every((id: number) => o.student_ids?.includes(id)));
+  const override = sameStudents
+    ? await req.canvas.assignments.updateOverride(Number(assignmentId), courseId, sameStudents.id, data)
+    : await req.canvas.assignments.createOverride(Number(assignmentId), courseId, data);
+  return res.json({ status: 'ok', data: override });
+});
+
+app.delete('/course/:courseId/assignments/:assignmentId/overrides/:overrideId', async (req: Request, res: Response) => {
+  logger.info('Deleting assignment override', { course: req.canvas.client.config.course.name, params: req.params });
+  const { courseId, assignmentId, overrideId } = req.params;
+  const override = await req.canvas.assignments.deleteOverride(Number(assignmentId), courseId, Number(overrideId));
+  return res.json({ status: 'ok', data: override });
+});
+
+app.get('/course/:courseId/assignments/:assignmentId', async (req: Request, res: Response) => {
+  logger.info('Getting assignment', { course: req.canvas.client.config.course.name, params: req.params });
+  const { courseId, assignmentId } = req.params;
+  const assignment = await req.canvas.assignments.get(Number(assignmentId), courseId);
+  return res.json({ status: 'ok', data: assignment });
+});
+
+app.post('/course/:courseId/assignments/:assignmentId', async (req: Request, res: Response) => {
+  logger.info('Updating assignment', { course: req.canvas.client.config.course.name, params: req.params, fields: Object.keys(req.body) });
+  const { courseId, assignmentId } = req.params;
+  const { description } = req.body;
+
+  const data: AssignmentUpdatedAttributes = {};
+  if (typeof description === 'string') {
+    data.description = description;
+  }
+
+  if (!Object.keys(data).length) {
+    logger.error('Attempted to update assignment without any fields');
+    return res.status(400).json({ status: 'error', message: 'Nothing to update' });
+  }
+
+  const assignment = await req.canvas.assignments.update(Number(assignmentId), courseId, data);
+  return res.json({ status: 'ok', data: assignment });
+});
+
+app.get('/course/:courseId/pages/:pageUrl', async (req: Request, res: Response) => {
+  logger.info('Getting page', { course: req.canvas.client.config.course.name, params: req.params });
+  const { courseId, pageUrl } = req.params;
+  const page = await req.canvas.pages.get(courseId, pageUrl);
+  return res.json({ status: 'ok', data: page });
+});
+
+app.post('/course/:courseId/pages/:pageUrl', async (req: Request, res: Response) => {
+  logger.info('Updating page', { course: req.canvas.client.config.course.name, params: req.params });
+  const { courseId, pageUrl } = req.params;
+  const { body } = req.body;
+
+  if (typeof body !== 'string') {
+    logger.error('Attempted to update page without body');
+    return res.status(400).json({ status: 'error', message: 'Page body is required' });
+  }
+
+  const page = await req.canvas.pages.update(courseId, pageUrl, { body });
+  return res.json({ status: 'ok', data: page });
+});
+
+app.get('/course/:courseId/assignments/:assignmentId/submissions', async (req: Request, res: Response) => {
+  logger.info('Getting assignment submissions', { course: req.canvas.client.config.course.name, params: req.params });
+  const { courseId, assignmentId } = req.params;
+  const submissions = await req.canvas.submissions.getAssignmentSubmissions(Number(assignmentId), Number(courseId), ['user', 'submission_comments']);
+  return res.json({ status: 'ok', data: submissions });
+});
+
+app.post('/course/:courseId/assignments/:assignmentId/submissions/:userId/grade', async (req: Request, res: Response) => {
+  logger.info('Grading submission', { course: req.canvas.client.config.course.name, params: req.params, body: req.body });
+  const { courseId, assignmentId, userId } = req.params;
+  const { score = null, comment = '' } = req.body;
+
+  if (score === null && !comment) {
+    logger.error('Attempted to grade without a score or comment');
+    return res.status(400).json({ status: 'error', message: 'A score or comment is required' });
+  }
+
+  if (score !== null) {
+    const assignment = await req.canvas.assignments.get(Number(assignmentId), courseId);
+    if (typeof score !== 'number' || score < 0 || score > assignment.points_possible) {
+      logger.error('Invalid score', { score, pointsPossible: assignment.points_possible });
+      return res.status(400).json({ status: 'error', message: `Score must be between 0 and ${assignment.points_possible}` });
+    }
+  }
+
+  const submission = await req.canvas.submissions.grade(
+    Number(assignmentId),
+    Number(courseId),
+    Number(userId),
+    score !== null ? { posted_grade: String(score) } : {},
+    comment ? { text_comment: comment } : undefined,
+  );
+
+  return res.json({ status: 'ok', data: submission });
+});
+
+app.post('/course/:courseId/grades', async (req: Request, res: Response) => {
+  logger.info('Updating grades', { course: req.canvas.client.config.course.name, count: req.body.grades?.length });
+  const { courseId } = req.params;
+  const { grades = [] } = req.body;
+
+  if (!grades.length) {
+    logger.error('Attempted to update grades without any grades');
+    return res.status(400).json({ status: 'error', message: 'Grades are required' });
+  }
+  const invalid = grades.find((g: any) => !g.assignmentId || !g.userId || typeof g.score !== 'number' || g.score < 0);
+  if (invalid) {
+    logger.error('Invalid grade', { grade: invalid });
+    return res.status(400).json({ status: 'error', message: `Invalid grade ${JSON.stringify(invalid)}` });
+  }
+
+  const progress = await req.canvas.submissions.updateGrades(Number(courseId), grades.map((g: any) => ({
+    assignment_id: g.assignmentId,
+    user_id: g.userId,
+    posted_grade: g.score,
+  })));
+  return res.json({ status: 'ok', data: progress });
+});
+
+app.get('/course/:courseId/students/:userId/missing', async (req: Request, res: Response) => {
+  logger.info('Getting missing assignments', { course: req.canvas.client.config.course.name, params: req.params });
+  const { courseId, userId } = req.params;
+  const assignments = await req.canvas.assignments.missingForUser(courseId, userId);
+  return res.json({ status: 'ok', data: assignments });
+});
+
+app.post('/course/:courseId/conversations', async (req: Request, res: Response) => {
+  logger.info('Sending conversation', { course: req.canvas.client.config.course.name, recipients: req.body.recipients, subject: req.body.subject });
+  const { courseId } = req.params;
+  const { recipients = [], subject = '', body = '' } = req.body;
+
+  if (!recipients.length || !body) {
+    logger.error('Attempted to send a conversation without recipients or body');
+    return res.status(400).json({ status: 'error', message: 'Recipients and body are required' });
+  }
+
+  const conversations = await req.canvas.conversations.create({
+    recipients,
+    subject,
+    body,
+    context_code: `course_${courseId}`,
+  });
+
+  return res.json({ status: 'ok', data: conversations });
+});
+
+app.post('/course/:courseId/announcements', async (req: Request, res: Response) => {
+  logger.info('Creating announcement', { course: req.canvas.client.config.course.name, title: req.body.title, delayedPostAt: req.body.delayedPostAt });
+  const { courseId } = req.params;
+  const { title = '', message = '', delayedPostAt = null } = req.body;
+
+  if (!title || !message) {
+    logger.error('Attempted to create announcement without title or message');
+    return res.status(400).json({ status: 'error', message: 'Title and message are required' });
+  }
+
+  const delayedPostDate = delayedPostAt ? new Date(delayedPostAt) : null;
+  if (delayedPostDate && isNaN(delayedPostDate.getTime())) {
+    logger.error('Invalid delayed post date', { delayedPostAt });
+    return res.status(400).json({ status: 'error', message: `Invalid delayed post date ${delayedPostAt}` });
+  }
+
+  const announcement = await req.canvas.announcements.create(courseId, {
+    title,
+    message,
+    delayed_post_at: delayedPostDate,
+  });
+
+  return res.json({ status: 'ok', data: announcement });
+});
+
+app.get('/course/:courseId/github', async (req: Request, res: Response) => {
+  logger.info('Getting GitHub team', { course: req.canvas.client.config.course.name });
+  return res.json({
+    status: 'ok',
+    data: {
+      organization: githubOrganization,
+      team: req.canvas.client.config.course.name,
+    },
+  });
+});
+
+app.get('/course/:courseId/github/usernames', async (req: Request, res: Response) => {
+  logger.info('Getting GitHub usernames', { course: req.canvas.client.config.course.name, column: req.query.column });
+  const { courseId } = req.params;
+  const columnTitle = String(req.query.column || 'GitHub');
+
+  const columns = await req.canvas.customColumns.getAll(courseId);
+  const column = columns.find((c) => c.title.toLowerCase() === columnTitle.toLowerCase());
+  if (!column) {
+    logger.error('Unable to find custom gradebook column', { column: columnTitle });
+    return res.status(404).json({ status: 'error', message: `Unable to find gradebook column ${columnTitle}` });
+  }
+
+  const data = await req.canvas.customColumns.data(courseId, column.id);
+  const usernames = data
+    .filter((datum) => datum.content?.trim())
+    .map((datum) => ({ user_id: datum.user_id, username: datum.content.trim() }));
+  return res.json({ status: 'ok', data: usernames });
+});
+
 // Start server
 app.listen(port, () => {
   logger.info(`Server is running on port ${port}`);
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type CreateAnnouncementRequest struct {
+	Title         string     `json:"title"`
+	Message       string     `json:"message"`
+	DelayedPostAt *time.Time `json:"delayedPostAt,omitempty"`
+}
+
+// CreateAnnouncement posts an announcement to the course. Message is HTML;
+// a DelayedPostAt in the future schedules it instead of posting right away.
+func (c *Client) CreateAnnouncement(req CreateAnnouncementRequest) error {
+	url := fmt.Sprintf("%s/course/%s/announcements", c.baseURL, c.courseId)
+	log := c.log.With(
+		"action", "create_announcement",
+		"url", url,
+		"title", req.Title,
+	)
+	log.Info("Creating announcement")
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to create announcement", "error", err)
+		return fmt.Errorf("failed to create announcement: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	log.Info("Successfully created announcement")
+	return nil
+}
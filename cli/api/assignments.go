@@ -0,0 +1,249 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type Assignment struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	Description       string     `json:"description"`
+	DueAt             *time.Time `json:"due_at"`
+	UnlockAt          *time.Time `json:"unlock_at"`
+	LockAt            *time.Time `json:"lock_at"`
+	PointsPossible    float64    `json:"points_possible"`
+	AssignmentGroupID int        `json:"assignment_group_id"`
+	Published         bool       `json:"published"`
+	OmitFromFinal     bool       `json:"omit_from_final_grade"`
+	HtmlURL           string     `json:"html_url"`
+}
+
+type AssignmentGroupRules struct {
+	DropLowest  int   `json:"drop_lowest"`
+	DropHighest int   `json:"drop_highest"`
+	NeverDrop   []int `json:"never_drop"`
+}
+
+type AssignmentGroup struct {
+	ID          int                  `json:"id"`
+	Name        string               `json:"name"`
+	Position    int                  `json:"position"`
+	Weight      float64              `json:"group_weight"`
+	Rules       AssignmentGroupRules `json:"rules"`
+	Assignments []Assignment         `json:"assignments"`
+}
+
+// AssignmentGroups are the course's groups with their assignments. Weighted
+// says whether the course grades by group weight or by total points.
+type AssignmentGroups struct {
+	Weighted bool              `json:"weighted"`
+	Groups   []AssignmentGroup `json:"groups"`
+}
+
+type UpdateAssignmentGroupRequest struct {
+	Weight float64 `json:"weight"`
+}
+
+type UpdateAssignmentRequest struct {
+	Description *string `json:"description,omitempty"`
+}
+
+func (c *Client) GetAssignments() ([]Assignment, error) {
+	url := fmt.Sprintf("%s/course/%s/assignments", c.baseURL, c.courseId)
+	log := c.log.With("action", "get_assignments", "url", url)
+	log.Info("Fetching assignments")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch assignments", "error", err)
+		return nil, fmt.Errorf("failed to fetch assignments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string       `json:"status"`
+		Data   []Assignment `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched assignments", "count", len(result.Data))
+	return result.Data, nil
+}
+
+func (c *Client) GetAssignmentGroups() (AssignmentGroups, error) {
+	url := fmt.Sprintf("%s/course/%s/assignment-groups", c.baseURL, c.courseId)
+	log := c.log.With("action", "get_assignment_groups", "url", url)
+	log.Info("Fetching assignment groups")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch assignment groups", "error", err)
+		return AssignmentGroups{}, fmt.Errorf("failed to fetch assignment groups: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return AssignmentGroups{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string           `json:"status"`
+		Data   AssignmentGroups `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return AssignmentGroups{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched assignment groups", "count", len(result.Data.Groups))
+	return result.Data, nil
+}
+
+func (c *Client) UpdateAssignmentGroup(groupID int, req UpdateAssignmentGroupRequest) error {
+	url := fmt.Sprintf("%s/course/%s/assignment-groups/%d", c.baseURL, c.courseId, groupID)
+	log := c.log.With(
+		"action", "update_assignment_group",
+		"url", url,
+		"group_id", groupID,
+	)
+	log.Info("Updating assignment group")
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to update assignment group", "error", err)
+		return fmt.Errorf("failed to update assignment group: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	log.Info("Successfully updated assignment group")
+	return nil
+}
+
+func (c *Client) GetAssignment(assignmentID int) (Assignment, error) {
+	url := fmt.Sprintf("%s/course/%s/assignments/%d", c.baseURL, c.courseId, assignmentID)
+	log := c.log.With(
+		"action", "get_assignment",
+		"url", url,
+		"assignment_id", assignmentID,
+	)
+	log.Info("Fetching assignment")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch assignment", "error", err)
+		return Assignment{}, fmt.Errorf("failed to fetch assignment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return Assignment{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string     `json:"status"`
+		Data   Assignment `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return Assignment{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched assignment")
+	return result.Data, nil
+}
+
+func (c *Client) UpdateAssignment(assignmentID int, req UpdateAssignmentRequest) error {
+	url := fmt.Sprintf("%s/course/%s/assignments/%d", c.baseURL, c.courseId, assignmentID)
+	log := c.log.With(
+		"action", "update_assignment",
+		"url", url,
+		"assignment_id", assignmentID,
+	)
+	log.Info("Updating assignment")
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to update assignment", "error", err)
+		return fmt.Errorf("failed to update assignment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	log.Info("Successfully updated assignment")
+	return nil
+}
+
+// GetMissingAssignments returns the assignments Canvas considers missing for
+// the student: past due with nothing submitted.
+func (c *Client) GetMissingAssignments(userID int) ([]Assignment, error) {
+	url := fmt.Sprintf("%s/course/%s/students/%d/missing", c.baseURL, c.courseId, userID)
+	log := c.log.With(
+		"action", "get_missing_assignments",
+		"url", url,
+		"user_id", userID,
+	)
+	log.Info("Fetching missing assignments")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch missing assignments", "error", err)
+		return nil, fmt.Errorf("failed to fetch missing assignments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string       `json:"status"`
+		Data   []Assignment `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched missing assignments", "count", len(result.Data))
+	return result.Data, nil
+}
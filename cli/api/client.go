@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
 )
@@ -91,6 +92,139 @@ func (c *Client) GetModules() ([]Module, error) {
 	return result.Data, nil
 }
 
+func (c *Client) UpdateModule(moduleID int, req UpdateModuleRequest) (Module, error) {
+	url := fmt.Sprintf("%s/course/%s/modules/%d", c.baseURL, c.courseId, moduleID)
+	log := c.log.With(
+		"action", "update_module",
+		"url", url,
+		"module_id", moduleID,
+	)
+	log.Info("Updating module")
+
+	if req.PrerequisiteModuleIDs == nil {
+		req.PrerequisiteModuleIDs = []int{}
+	}
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return Module{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to update module", "error", err)
+		return Module{}, fmt.Errorf("failed to update module: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return Module{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string `json:"status"`
+		Data   Module `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return Module{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully updated module")
+	return result.Data, nil
+}
+
+// UpdateModuleLayout reorders, indents and moves out the module's items and
+// returns the lessons they make up afterwards.
+func (c *Client) UpdateModuleLayout(moduleID int, req UpdateModuleLayoutRequest) ([]ModuleNode, error) {
+	url := fmt.Sprintf("%s/course/%s/modules/%d/layout", c.baseURL, c.courseId, moduleID)
+	log := c.log.With(
+		"action", "update_module_layout",
+		"url", url,
+		"module_id", moduleID,
+	)
+	log.Info("Updating module layout", "items", len(req.Items), "moves", len(req.Moves))
+
+	if req.Moves == nil {
+		req.Moves = []ModuleItemMove{}
+	}
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to update module layout", "error", err)
+		return nil, fmt.Errorf("failed to update module layout: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string       `json:"status"`
+		Data   []ModuleNode `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully updated module layout", "lessons", len(result.Data))
+	return result.Data, nil
+}
+
+// CreateModuleItems adds the items to the module and returns its lessons
+// afterwards.
+func (c *Client) CreateModuleItems(moduleID int, req CreateModuleItemsRequest) ([]ModuleNode, error) {
+	url := fmt.Sprintf("%s/course/%s/modules/%d/items", c.baseURL, c.courseId, moduleID)
+	log := c.log.With(
+		"action", "create_module_items",
+		"url", url,
+		"module_id", moduleID,
+	)
+	log.Info("Creating module items", "items", len(req.Items), "position", req.Position)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to create module items", "error", err)
+		return nil, fmt.Errorf("failed to create module items: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string       `json:"status"`
+		Data   []ModuleNode `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully created module items", "lessons", len(result.Data))
+	return result.Data, nil
+}
+
 func (c *Client) GetModuleItems(moduleId int) ([]ModuleNode, error) {
 	url := fmt.Sprintf("%s/course/%s/modules/%d", c.baseURL, c.courseId, moduleId)
 	log := c.log.With(
@@ -140,14 +274,47 @@ type EnrollmentGrade struct {
 }
 
 type Module struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID                        int        `json:"id"`
+	Name                      string     `json:"name"`
+	Position                  int        `json:"position"`
+	UnlockAt                  *time.Time `json:"unlock_at"`
+	RequireSequentialProgress bool       `json:"require_sequential_progress"`
+	PrerequisiteModuleIDs     []int      `json:"prerequisite_module_ids"`
+	ItemsCount                int        `json:"items_count"`
+	Published                 bool       `json:"published"`
+}
+
+// UpdateModuleRequest sets every module setting at once. A nil UnlockAt
+// and empty PrerequisiteModuleIDs clear them.
+type UpdateModuleRequest struct {
+	UnlockAt                  *time.Time `json:"unlockAt"`
+	PrerequisiteModuleIDs     []int      `json:"prerequisiteModuleIds"`
+	RequireSequentialProgress bool       `json:"requireSequentialProgress"`
+	Published                 bool       `json:"published"`
 }
 
 type Lesson struct {
-	ID    int    `json:"id"`
-	Title string `json:"title"`
-	Type  string `json:"type"`
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Type        string `json:"type"`
+	ContentID   int    `json:"content_id"`
+	ExternalURL string `json:"external_url"`
+	PageURL     string `json:"page_url"`
+	Published   bool   `json:"published"`
+	Position    int    `json:"position"`
+	Indent      int    `json:"indent"`
+	HtmlURL     string `json:"html_url"`
+
+	ContentDetails *ContentDetails `json:"content_details"`
+}
+
+// ContentDetails are the dates and points of the assignment, quiz or
+// discussion behind a module item.
+type ContentDetails struct {
+	PointsPossible float64    `json:"points_possible"`
+	DueAt          *time.Time `json:"due_at"`
+	UnlockAt       *time.Time `json:"unlock_at"`
+	LockAt         *time.Time `json:"lock_at"`
 }
 
 type ModuleNode struct {
@@ -155,6 +322,45 @@ type ModuleNode struct {
 	Children []Lesson `json:"children"`
 }
 
+// ModuleItemLayout places an item: its order in the request is its order
+// in the module, and indent 0 starts a lesson.
+type ModuleItemLayout struct {
+	ID     int `json:"id"`
+	Indent int `json:"indent"`
+}
+
+// ModuleItemMove sends an item to the end of another module.
+type ModuleItemMove struct {
+	ID       int `json:"id"`
+	ModuleID int `json:"moduleId"`
+}
+
+type UpdateModuleLayoutRequest struct {
+	Items []ModuleItemLayout `json:"items"`
+	Moves []ModuleItemMove   `json:"moves"`
+}
+
+// NewModuleItem is an item to add to a module. Pages and assignments are
+// created along with it.
+type NewModuleItem struct {
+	Type            string   `json:"type"`
+	Title           string   `json:"title"`
+	Indent          int      `json:"indent"`
+	Body            string   `json:"body,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	Points          float64  `json:"points,omitempty"`
+	SubmissionTypes []string `json:"submissionTypes,omitempty"`
+	URL             string   `json:"url,omitempty"`
+	NewTab          *bool    `json:"newTab,omitempty"`
+}
+
+// CreateModuleItemsRequest adds the items in order starting at Position,
+// or at the end of the module when Position is 0.
+type CreateModuleItemsRequest struct {
+	Position int             `json:"position,omitempty"`
+	Items    []NewModuleItem `json:"items"`
+}
+
 type UpdateLessonRequest struct {
 	LessonID int    `json:"lessonId"`
 	Action   string `json:"action"`
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type SendConversationRequest struct {
+	Recipients []int  `json:"recipients"`
+	Subject    string `json:"subject"`
+	Body       string `json:"body"`
+}
+
+// SendConversation starts a new Canvas conversation (inbox message) in the
+// context of the course.
+func (c *Client) SendConversation(req SendConversationRequest) error {
+	url := fmt.Sprintf("%s/course/%s/conversations", c.baseURL, c.courseId)
+	log := c.log.With(
+		"action", "send_conversation",
+		"url", url,
+		"recipients", req.Recipients,
+	)
+	log.Info("Sending conversation")
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to send conversation", "error", err)
+		return fmt.Errorf("failed to send conversation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	log.Info("Successfully sent conversation")
+	return nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+)
+
+// GitHubTeam is the organization and team that publishing grants repository
+// access to. The team is named after the course.
+type GitHubTeam struct {
+	Organization string `json:"organization"`
+	Team         string `json:"team"`
+}
+
+func (c *Client) GetGitHubTeam() (GitHubTeam, error) {
+	url := fmt.Sprintf("%s/course/%s/github", c.baseURL, c.courseId)
+	log := c.log.With("action", "get_github_team", "url", url)
+	log.Info("Fetching GitHub team")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch GitHub team", "error", err)
+		return GitHubTeam{}, fmt.Errorf("failed to fetch GitHub team: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return GitHubTeam{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string     `json:"status"`
+		Data   GitHubTeam `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return GitHubTeam{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched GitHub team", "organization", result.Data.Organization, "team", result.Data.Team)
+	return result.Data, nil
+}
+
+// GitHubUsername is a student's GitHub username from the course's custom
+// gradebook column.
+type GitHubUsername struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// GetGitHubUsernames reads the custom gradebook column with the given title.
+func (c *Client) GetGitHubUsernames(column string) ([]GitHubUsername, error) {
+	url := fmt.Sprintf("%s/course/%s/github/usernames?column=%s", c.baseURL, c.courseId, neturl.QueryEscape(column))
+	log := c.log.With("action", "get_github_usernames", "url", url, "column", column)
+	log.Info("Fetching GitHub usernames")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch GitHub usernames", "error", err)
+		return nil, fmt.Errorf("failed to fetch GitHub usernames: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		log.Error("Gradebook column not found")
+		return nil, fmt.Errorf("no gradebook column named %q", column)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string           `json:"status"`
+		Data   []GitHubUsername `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched GitHub usernames", "count", len(result.Data))
+	return result.Data, nil
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AssignmentOverride gives some students, or a section, their own dates
+// for an assignment.
+type AssignmentOverride struct {
+	ID              int        `json:"id"`
+	AssignmentID    int        `json:"assignment_id"`
+	AssignmentName  string     `json:"assignment_name"`
+	AssignmentDueAt *time.Time `json:"assignment_due_at"`
+	Title           string     `json:"title"`
+	StudentIDs      []int      `json:"student_ids"`
+	CourseSectionID int        `json:"course_section_id"`
+	DueAt           *time.Time `json:"due_at"`
+	UnlockAt        *time.Time `json:"unlock_at"`
+	LockAt          *time.Time `json:"lock_at"`
+}
+
+// Active reports whether the override still matters at now: its lock date,
+// or due date when there's no lock date, hasn't passed.
+func (o AssignmentOverride) Active(now time.Time) bool {
+	end := o.LockAt
+	if end == nil {
+		end = o.DueAt
+	}
+	return end == nil || end.After(now)
+}
+
+// CreateOverrideRequest moves the due date for the students. An existing
+// override for exactly the same students is updated instead.
+type CreateOverrideRequest struct {
+	StudentIDs []int      `json:"studentIds"`
+	Title      string     `json:"title,omitempty"`
+	DueAt      time.Time  `json:"dueAt"`
+	LockAt     *time.Time `json:"lockAt,omitempty"`
+}
+
+// GetOverrides returns the overrides on every assignment in the course.
+func (c *Client) GetOverrides() ([]AssignmentOverride, error) {
+	url := fmt.Sprintf("%s/course/%s/overrides", c.baseURL, c.courseId)
+	log := c.log.With("action", "get_overrides", "url", url)
+	log.Info("Fetching assignment overrides")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch assignment overrides", "error", err)
+		return nil, fmt.Errorf("failed to fetch assignment overrides: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string               `json:"status"`
+		Data   []AssignmentOverride `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched assignment overrides", "count", len(result.Data))
+	return result.Data, nil
+}
+
+func (c *Client) CreateOverride(assignmentID int, req CreateOverrideRequest) (AssignmentOverride, error) {
+	url := fmt.Sprintf("%s/course/%s/assignments/%d/overrides", c.baseURL, c.courseId, assignmentID)
+	log := c.log.With(
+		"action", "create_override",
+		"url", url,
+		"assignment_id", assignmentID,
+	)
+	log.Info("Creating assignment override")
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return AssignmentOverride{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to create assignment override", "error", err)
+		return AssignmentOverride{}, fmt.Errorf("failed to create assignment override: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return AssignmentOverride{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string             `json:"status"`
+		Data   AssignmentOverride `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return AssignmentOverride{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully created assignment override", "override_id", result.Data.ID)
+	return result.Data, nil
+}
+
+func (c *Client) DeleteOverride(assignmentID int, overrideID int) error {
+	url := fmt.Sprintf("%s/course/%s/assignments/%d/overrides/%d", c.baseURL, c.courseId, assignmentID, overrideID)
+	log := c.log.With(
+		"action", "delete_override",
+		"url", url,
+		"assignment_id", assignmentID,
+		"override_id", overrideID,
+	)
+	log.Info("Deleting assignment override")
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		log.Error("Failed to create request", "error", err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Error("Failed to delete assignment override", "error", err)
+		return fmt.Errorf("failed to delete assignment override: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	log.Info("Successfully deleted assignment override")
+	return nil
+}
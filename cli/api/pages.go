@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+)
+
+type Page struct {
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Published bool   `json:"published"`
+	HtmlURL   string `json:"html_url"`
+}
+
+type UpdatePageRequest struct {
+	Body string `json:"body"`
+}
+
+func (c *Client) GetPage(pageURL string) (Page, error) {
+	url := fmt.Sprintf("%s/course/%s/pages/%s", c.baseURL, c.courseId, neturl.PathEscape(pageURL))
+	log := c.log.With("action", "get_page", "url", url, "page_url", pageURL)
+	log.Info("Fetching page")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch page", "error", err)
+		return Page{}, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return Page{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string `json:"status"`
+		Data   Page   `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return Page{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched page")
+	return result.Data, nil
+}
+
+func (c *Client) UpdatePage(pageURL string, req UpdatePageRequest) error {
+	url := fmt.Sprintf("%s/course/%s/pages/%s", c.baseURL, c.courseId, neturl.PathEscape(pageURL))
+	log := c.log.With("action", "update_page", "url", url, "page_url", pageURL)
+	log.Info("Updating page")
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to update page", "error", err)
+		return fmt.Errorf("failed to update page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	log.Info("Successfully updated page")
+	return nil
+}
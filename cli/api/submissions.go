@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+type Submission struct {
+	ID             int                  `json:"id"`
+	AssignmentID   int                  `json:"assignment_id"`
+	UserID         int                  `json:"user_id"`
+	WorkflowState  string               `json:"workflow_state"`
+	Score          *float64             `json:"score"`
+	Grade          string               `json:"grade"`
+	Attempt        int                  `json:"attempt"`
+	SubmittedAt    *time.Time           `json:"submitted_at"`
+	GradedAt       *time.Time           `json:"graded_at"`
+	Late           bool                 `json:"late"`
+	Missing        bool                 `json:"missing"`
+	Excused        bool                 `json:"excused"`
+	SecondsLate    int                  `json:"seconds_late"`
+	SubmissionType string               `json:"submission_type"`
+	Body           string               `json:"body"`
+	URL            string               `json:"url"`
+	PreviewURL     string               `json:"preview_url"`
+	User           User                 `json:"user"`
+	Assignment     SubmissionAssignment `json:"assignment"`
+	// Comments are only included for a single assignment's submissions.
+	Comments []SubmissionComment `json:"submission_comments"`
+}
+
+type SubmissionComment struct {
+	ID         int       `json:"id"`
+	AuthorName string    `json:"author_name"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SubmissionAssignment is the subset of the assignment Canvas embeds in a
+// submission when asked to include it.
+type SubmissionAssignment struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	PointsPossible float64    `json:"points_possible"`
+	DueAt          *time.Time `json:"due_at"`
+	HtmlURL        string     `json:"html_url"`
+}
+
+type SubmissionStatus string
+
+const (
+	StatusGraded      SubmissionStatus = "graded"
+	StatusSubmitted   SubmissionStatus = "submitted"
+	StatusLate        SubmissionStatus = "late"
+	StatusMissing     SubmissionStatus = "missing"
+	StatusExcused     SubmissionStatus = "excused"
+	StatusUnsubmitted SubmissionStatus = "unsubmitted"
+)
+
+// Status collapses Canvas' workflow state and late/missing flags into the
+// single state instructors care about. Late wins over submitted so late work
+// stands out until it has been graded.
+func (s Submission) Status() SubmissionStatus {
+	switch {
+	case s.Excused:
+		return StatusExcused
+	case s.WorkflowState == "graded":
+		return StatusGraded
+	case s.Late:
+		return StatusLate
+	case s.WorkflowState == "submitted" || s.WorkflowState == "pending_review":
+		return StatusSubmitted
+	case s.Missing:
+		return StatusMissing
+	}
+	return StatusUnsubmitted
+}
+
+type GradeSubmissionRequest struct {
+	Score   *float64 `json:"score,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// GradeChange sets one student's score on one assignment as part of a bulk
+// grade update.
+type GradeChange struct {
+	AssignmentID int     `json:"assignmentId"`
+	UserID       int     `json:"userId"`
+	Score        float64 `json:"score"`
+}
+
+// Progress tracks a background job Canvas queued, such as a bulk grade
+// update.
+type Progress struct {
+	ID            int    `json:"id"`
+	WorkflowState string `json:"workflow_state"`
+	Message       string `json:"message"`
+	URL           string `json:"url"`
+}
+
+// GetSubmissions returns submissions across every assignment in the course,
+// limited to the given workflow states (e.g. "submitted") when any are passed.
+func (c *Client) GetSubmissions(workflowStates ...string) ([]Submission, error) {
+	url := fmt.Sprintf("%s/course/%s/submissions", c.baseURL, c.courseId)
+	if len(workflowStates) > 0 {
+		url += "?workflow_state=" + neturl.QueryEscape(strings.Join(workflowStates, ","))
+	}
+	log := c.log.With("action", "get_submissions", "url", url)
+	log.Info("Fetching submissions")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch submissions", "error", err)
+		return nil, fmt.Errorf("failed to fetch submissions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string       `json:"status"`
+		Data   []Submission `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched submissions", "count", len(result.Data))
+	return result.Data, nil
+}
+
+func (c *Client) GetAssignmentSubmissions(assignmentID int) ([]Submission, error) {
+	url := fmt.Sprintf("%s/course/%s/assignments/%d/submissions", c.baseURL, c.courseId, assignmentID)
+	log := c.log.With(
+		"action", "get_assignment_submissions",
+		"url", url,
+		"assignment_id", assignmentID,
+	)
+	log.Info("Fetching assignment submissions")
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		log.Error("Failed to fetch assignment submissions", "error", err)
+		return nil, fmt.Errorf("failed to fetch assignment submissions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string       `json:"status"`
+		Data   []Submission `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully fetched assignment submissions", "count", len(result.Data))
+	return result.Data, nil
+}
+
+func (c *Client) GradeSubmission(assignmentID int, userID int, req GradeSubmissionRequest) error {
+	url := fmt.Sprintf("%s/course/%s/assignments/%d/submissions/%d/grade", c.baseURL, c.courseId, assignmentID, userID)
+	log := c.log.With(
+		"action", "grade_submission",
+		"url", url,
+		"assignment_id", assignmentID,
+		"user_id", userID,
+	)
+	log.Info("Grading submission")
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to grade submission", "error", err)
+		return fmt.Errorf("failed to grade submission: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	log.Info("Successfully graded submission")
+	return nil
+}
+
+// UpdateGrades posts many scores in one request. Canvas applies them in the
+// background, so the returned progress only says the job was queued.
+func (c *Client) UpdateGrades(changes []GradeChange) (Progress, error) {
+	url := fmt.Sprintf("%s/course/%s/grades", c.baseURL, c.courseId)
+	log := c.log.With(
+		"action", "update_grades",
+		"url", url,
+		"count", len(changes),
+	)
+	log.Info("Updating grades")
+
+	jsonData, err := json.Marshal(map[string]any{"grades": changes})
+	if err != nil {
+		log.Error("Failed to marshal request", "error", err)
+		return Progress{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Error("Failed to update grades", "error", err)
+		return Progress{}, fmt.Errorf("failed to update grades: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected status code", "status", resp.StatusCode)
+		return Progress{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status string   `json:"status"`
+		Data   Progress `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return Progress{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	log.Info("Successfully queued grade update", "progress_id", result.Data.ID)
+	return result.Data, nil
+}
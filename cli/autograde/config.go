@@ -0,0 +1,87 @@
+// Package autograde runs an assignment's tests in each checked out student
+// repository and turns the results into scores.
+package autograde
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is looked for in the assignment's workspace directory.
+const ConfigFile = "autograde.yaml"
+
+type Format string
+
+const (
+	// FormatExit scores on the exit code alone: full marks or nothing.
+	FormatExit  Format = "exit"
+	FormatTAP   Format = "tap"
+	FormatJUnit Format = "junit"
+)
+
+// Config describes how to test one assignment.
+type Config struct {
+	// Setup runs before Command, e.g. to install dependencies. Its output
+	// is not parsed. Both see only PATH, LANG, CI and a temporary HOME from
+	// the environment.
+	Setup   string `yaml:"setup,omitempty"`
+	Command string `yaml:"command"`
+	Format  Format `yaml:"format"`
+	// JUnitPath is a glob, relative to the repository, for FormatJUnit.
+	JUnitPath string `yaml:"junit_path,omitempty"`
+	// Timeout bounds setup and command together.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// MemoryMB and CPUSeconds are applied with ulimit. Zero means no limit.
+	MemoryMB   int `yaml:"memory_mb,omitempty"`
+	CPUSeconds int `yaml:"cpu_seconds,omitempty"`
+	// Points overrides the assignment's points possible.
+	Points float64 `yaml:"points,omitempty"`
+}
+
+const DefaultTimeout = 2 * time.Minute
+
+// LoadConfig reads the config at path and fills in defaults.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, fmt.Errorf("no autograder config at %s", path)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read autograder config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse autograder config: %w", err)
+	}
+
+	if config.Command == "" {
+		return Config{}, fmt.Errorf("autograder config %s has no command", path)
+	}
+	if config.Format == "" {
+		config.Format = FormatExit
+	}
+	switch config.Format {
+	case FormatExit, FormatTAP:
+	case FormatJUnit:
+		if config.JUnitPath == "" {
+			return Config{}, fmt.Errorf("autograder config %s needs a junit_path for junit results", path)
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown result format %q (expected exit, tap or junit)", config.Format)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return config, nil
+}
+
+// DefaultConfigPath is the config for the assignment checked out in dir.
+func DefaultConfigPath(dir string) string {
+	return filepath.Join(dir, ConfigFile)
+}
@@ -0,0 +1,80 @@
+package autograde
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DraftScore is a reviewed row of the autograder report, ready to post.
+type DraftScore struct {
+	Student string
+	UserID  int
+	Score   float64
+	Passed  int
+	Failed  int
+}
+
+// Comment explains the score to the student.
+func (d DraftScore) Comment() string {
+	if d.Passed+d.Failed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Autograder: %d of %d tests passed.", d.Passed, d.Passed+d.Failed)
+}
+
+// ReadDraftScores reads a CSV autograder report after review. Columns are
+// found by header name so they can be reordered, and rows with a blank score
+// are skipped so instructors can hold back a row by clearing it. Scores must
+// be between 0 and pointsPossible.
+func ReadDraftScores(r io.Reader, pointsPossible float64) ([]DraftScore, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("report is empty")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"user id", "score"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("report has no %q column", required)
+		}
+	}
+	cell := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var drafts []DraftScore
+	for line, record := range records[1:] {
+		score := cell(record, "score")
+		if score == "" {
+			continue
+		}
+
+		draft := DraftScore{Student: cell(record, "student")}
+		if draft.UserID, err = strconv.Atoi(cell(record, "user id")); err != nil || draft.UserID == 0 {
+			return nil, fmt.Errorf("line %d: invalid user id %q", line+2, cell(record, "user id"))
+		}
+		if draft.Score, err = strconv.ParseFloat(score, 64); err != nil || math.IsNaN(draft.Score) || math.IsInf(draft.Score, 0) {
+			return nil, fmt.Errorf("line %d: invalid score %q", line+2, score)
+		}
+		if draft.Score < 0 || draft.Score > pointsPossible {
+			return nil, fmt.Errorf("line %d: score %g must be between 0 and %g", line+2, draft.Score, pointsPossible)
+		}
+		draft.Passed, _ = strconv.Atoi(cell(record, "passed"))
+		draft.Failed, _ = strconv.Atoi(cell(record, "failed"))
+		drafts = append(drafts, draft)
+	}
+	return drafts, nil
+}
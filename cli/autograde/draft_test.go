@@ -0,0 +1,50 @@
+package autograde
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadDraftScores(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  string
+		want    []DraftScore
+		wantErr bool
+	}{
+		{
+			name:   "blank scores are held back",
+			report: "Student,User ID,Passed,Failed,Score\nAda,1,3,1,7.5\nBob,2,0,4,\n",
+			want:   []DraftScore{{Student: "Ada", UserID: 1, Score: 7.5, Passed: 3, Failed: 1}},
+		},
+		{
+			name:   "columns found by name",
+			report: "score,user id\n10,3\n",
+			want:   []DraftScore{{UserID: 3, Score: 10}},
+		},
+		{name: "not a number", report: "User ID,Score\n1,NaN\n", wantErr: true},
+		{name: "infinite", report: "User ID,Score\n1,Inf\n", wantErr: true},
+		{name: "negative", report: "User ID,Score\n1,-1\n", wantErr: true},
+		{name: "over points possible", report: "User ID,Score\n1,11\n", wantErr: true},
+		{name: "missing user id", report: "Student,Score\nAda,5\n", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReadDraftScores(strings.NewReader(tc.report), 10)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ReadDraftScores() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadDraftScores() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ReadDraftScores() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package autograde
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Counts are the test totals parsed from a run.
+type Counts struct {
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+func (c Counts) Total() int {
+	return c.Passed + c.Failed
+}
+
+// Only unindented lines count: indented results and plans belong to subtests,
+// which their parent test already summarises.
+var (
+	tapResult = regexp.MustCompile(`^(not )?ok\b(?:\s+\d+)?([^#]*)(?:#\s*(\w+))?`)
+	tapPlan   = regexp.MustCompile(`^1\.\.(\d+)`)
+)
+
+// ParseTAP counts the results in TAP output. Tests marked SKIP are counted as
+// skipped and TODO tests are ignored, as the TAP spec says. Planned tests
+// that never reported count as failures, so a crash partway through isn't
+// rewarded.
+func ParseTAP(r io.Reader) (Counts, error) {
+	var counts Counts
+	planned := -1
+	seen := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := tapPlan.FindStringSubmatch(line); match != nil && planned < 0 {
+			fmt.Sscanf(match[1], "%d", &planned)
+			continue
+		}
+		match := tapResult.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		seen++
+		switch strings.ToUpper(match[3]) {
+		case "SKIP":
+			counts.Skipped++
+			continue
+		case "TODO":
+			continue
+		}
+		if match[1] == "" {
+			counts.Passed++
+		} else {
+			counts.Failed++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Counts{}, fmt.Errorf("failed to read tap output: %w", err)
+	}
+
+	if seen == 0 {
+		return Counts{}, fmt.Errorf("no TAP results in output")
+	}
+	if planned > seen {
+		counts.Failed += planned - seen
+	}
+	return counts, nil
+}
+
+type junitSuite struct {
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+	Cases    []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Failure *struct{} `xml:"failure"`
+	Error   *struct{} `xml:"error"`
+	Skipped *struct{} `xml:"skipped"`
+}
+
+// ParseJUnit counts the results in a JUnit XML report, with either a
+// <testsuites> or a single <testsuite> root. Test cases are counted directly
+// when present since not every reporter fills in the suite totals.
+func ParseJUnit(r io.Reader) (Counts, error) {
+	var root junitSuite
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return Counts{}, fmt.Errorf("failed to parse junit report: %w", err)
+	}
+	return countSuite(root), nil
+}
+
+func countSuite(suite junitSuite) Counts {
+	var counts Counts
+	if len(suite.Cases) > 0 {
+		for _, c := range suite.Cases {
+			switch {
+			case c.Skipped != nil:
+				counts.Skipped++
+			case c.Failure != nil || c.Error != nil:
+				counts.Failed++
+			default:
+				counts.Passed++
+			}
+		}
+	} else if len(suite.Suites) == 0 {
+		failed := suite.Failures + suite.Errors
+		counts.Failed = failed
+		counts.Skipped = suite.Skipped
+		counts.Passed = max(suite.Tests-failed-suite.Skipped, 0)
+	}
+
+	for _, child := range suite.Suites {
+		c := countSuite(child)
+		counts.Passed += c.Passed
+		counts.Failed += c.Failed
+		counts.Skipped += c.Skipped
+	}
+	return counts
+}
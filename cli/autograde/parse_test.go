@@ -0,0 +1,113 @@
+package autograde
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTAP(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    Counts
+		wantErr bool
+	}{
+		{
+			name:   "all passing",
+			output: "TAP version 13\n1..2\nok 1 - adds\nok 2 - subtracts\n",
+			want:   Counts{Passed: 2},
+		},
+		{
+			name:   "failures",
+			output: "1..3\nok 1\nnot ok 2 - divides\nok 3\n",
+			want:   Counts{Passed: 2, Failed: 1},
+		},
+		{
+			name:   "plan short of results counts the rest as failed",
+			output: "1..5\nok 1\nok 2\nnot ok 3\n",
+			want:   Counts{Passed: 2, Failed: 3},
+		},
+		{
+			name:   "plan at the end",
+			output: "ok 1\nok 2\n1..4\n",
+			want:   Counts{Passed: 2, Failed: 2},
+		},
+		{
+			name:   "skip and todo",
+			output: "1..3\nok 1 # SKIP no network\nnot ok 2 # TODO later\nok 3\n",
+			want:   Counts{Passed: 1, Skipped: 1},
+		},
+		{
+			name:   "indented subtests and output are ignored",
+			output: "# Subtest: parser\n    ok 1 - a\n    not ok 2 - b\n    1..2\nnot ok 1 - parser\n  ok from stdout\nok 2 - printer\n1..2\n",
+			want:   Counts{Passed: 1, Failed: 1},
+		},
+		{
+			name:    "no results",
+			output:  "npm ERR! missing script: test\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseTAP(strings.NewReader(tc.output))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTAP() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTAP() error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("ParseTAP() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseJUnit(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		want   Counts
+	}{
+		{
+			name: "test cases",
+			report: `<testsuite tests="4">
+				<testcase name="a"/>
+				<testcase name="b"><failure/></testcase>
+				<testcase name="c"><error/></testcase>
+				<testcase name="d"><skipped/></testcase>
+			</testsuite>`,
+			want: Counts{Passed: 1, Failed: 2, Skipped: 1},
+		},
+		{
+			name:   "suite totals only",
+			report: `<testsuite tests="10" failures="2" errors="1" skipped="3"/>`,
+			want:   Counts{Passed: 4, Failed: 3, Skipped: 3},
+		},
+		{
+			name: "nested suites",
+			report: `<testsuites>
+				<testsuite><testcase/><testcase><failure/></testcase></testsuite>
+				<testsuite tests="3" failures="1"/>
+			</testsuites>`,
+			want: Counts{Passed: 3, Failed: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseJUnit(strings.NewReader(tc.report))
+			if err != nil {
+				t.Fatalf("ParseJUnit() error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("ParseJUnit() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package autograde
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/workspace"
+)
+
+// maxOutput caps how much test output is kept per repository.
+const maxOutput = 1 << 20
+
+type Result struct {
+	workspace.Target
+	Counts
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration"`
+	// Score is nil when the run produced no usable results, so the row
+	// needs an instructor's attention rather than a zero.
+	Score  *float64 `json:"score"`
+	Error  string   `json:"error,omitempty"`
+	Output string   `json:"-"`
+}
+
+// Run tests each target, at most concurrency at a time, and scores it out of
+// points. Results are in the same order as targets.
+func Run(ctx context.Context, config Config, targets []workspace.Target, points float64, concurrency int) []Result {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	if config.Points > 0 {
+		points = config.Points
+	}
+
+	results := make([]Result, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runTarget(ctx, config, target, points)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func runTarget(ctx context.Context, config Config, target workspace.Target, points float64) Result {
+	result := Result{Target: target}
+
+	if _, err := os.Stat(target.Dir); err != nil {
+		result.Error = "repository is not checked out"
+		return result
+	}
+
+	// Stale reports from an earlier run would be mistaken for this one.
+	if config.Format == FormatJUnit {
+		stale, _ := filepath.Glob(filepath.Join(target.Dir, config.JUnitPath))
+		for _, path := range stale {
+			os.Remove(path)
+		}
+	}
+
+	home, err := os.MkdirTemp("", "autograde-home-*")
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create home directory: %v", err)
+		return result
+	}
+	defer os.RemoveAll(home)
+
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	output := &limitedBuffer{limit: maxOutput}
+	cmd := exec.CommandContext(ctx, "sh", "-c", script(config))
+	cmd.Dir = target.Dir
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.Env = environment(config, home)
+	cmd.WaitDelay = 5 * time.Second
+	killGroup(cmd)
+
+	start := time.Now()
+	err = cmd.Run()
+	result.Duration = time.Since(start).Round(time.Millisecond)
+	result.Output = output.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("timed out after %s", config.Timeout)
+		return result
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.ExitCode = -1
+		result.Error = err.Error()
+		return result
+	}
+
+	counts, err := parseResults(config, target.Dir, result.Output, result.ExitCode)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Counts = counts
+
+	score := 0.0
+	if counts.Total() > 0 {
+		score = math.Round(points*float64(counts.Passed)/float64(counts.Total())*100) / 100
+	}
+	result.Score = &score
+	return result
+}
+
+// script runs setup and the test command under the configured limits. The
+// commands come in through the environment so they need no quoting, and the
+// test command is exec'd so a timeout kills it rather than just the outer
+// shell.
+func script(config Config) string {
+	var b bytes.Buffer
+	if config.CPUSeconds > 0 {
+		fmt.Fprintf(&b, "ulimit -t %d || exit 125\n", config.CPUSeconds)
+	}
+	if config.MemoryMB > 0 {
+		fmt.Fprintf(&b, "ulimit -v %d || exit 125\n", config.MemoryMB*1024)
+	}
+	if config.Setup != "" {
+		b.WriteString("sh -c \"$AUTOGRADE_SETUP\" || exit $?\n")
+	}
+	b.WriteString("exec sh -c \"$AUTOGRADE_COMMAND\"\n")
+	return b.String()
+}
+
+// environment is everything the student's code sees: PATH, LANG, CI, a
+// throwaway HOME and the commands to run. Nothing else is passed through, so
+// GITHUB_TOKEN and the Canvas credentials never reach student code.
+func environment(config Config, home string) []string {
+	lang := os.Getenv("LANG")
+	if lang == "" {
+		lang = "C.UTF-8"
+	}
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + home,
+		"LANG=" + lang,
+		"CI=true",
+		"AUTOGRADE_SETUP=" + config.Setup,
+		"AUTOGRADE_COMMAND=" + config.Command,
+	}
+}
+
+func parseResults(config Config, dir string, output string, exitCode int) (Counts, error) {
+	switch config.Format {
+	case FormatTAP:
+		return ParseTAP(bytes.NewBufferString(output))
+	case FormatJUnit:
+		paths, err := filepath.Glob(filepath.Join(dir, config.JUnitPath))
+		if err != nil {
+			return Counts{}, fmt.Errorf("bad junit_path: %w", err)
+		}
+		if len(paths) == 0 {
+			return Counts{}, fmt.Errorf("no junit report at %s (exit code %d)", config.JUnitPath, exitCode)
+		}
+		var counts Counts
+		for _, path := range paths {
+			file, err := os.Open(path)
+			if err != nil {
+				return Counts{}, err
+			}
+			c, err := ParseJUnit(file)
+			file.Close()
+			if err != nil {
+				return Counts{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+			}
+			counts.Passed += c.Passed
+			counts.Failed += c.Failed
+			counts.Skipped += c.Skipped
+		}
+		return counts, nil
+	}
+
+	if exitCode == 0 {
+		return Counts{Passed: 1}, nil
+	}
+	return Counts{Failed: 1}, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and drops the rest
+// without failing the writer.
+type limitedBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,939 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/autograde"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/export"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/feedback"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/gradebook"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/latepolicy"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/linkcheck"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/lint"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/roster"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/similarity"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/views"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/workspace"
+)
+
+// runCommand handles the non-interactive subcommands. Running the binary
+// without arguments starts the TUI instead.
+func runCommand(args []string) error {
+	log := logger.With("component", "commands", "command", args[0])
+	log.Info("Running command", "args", args[1:])
+
+	switch args[0] {
+	case "enrollments":
+		return runEnrollmentsCommand(args[1:])
+	case "feedback":
+		return runFeedbackCommand(args[1:])
+	case "lint":
+		return runLintCommand(args[1:])
+	case "links":
+		return runLinksCommand(args[1:])
+	case "github":
+		return runGitHubCommand(args[1:])
+	case "clone":
+		return runCloneCommand(args[1:])
+	case "autograde":
+		return runAutogradeCommand(args[1:])
+	case "similarity":
+		return runSimilarityCommand(args[1:])
+	case "gradebook":
+		return runGradebookCommand(args[1:])
+	case "late":
+		return runLateCommand(args[1:])
+	case "outline":
+		return runOutlineCommand(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func runEnrollmentsCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: enrollments export [flags]")
+	}
+
+	switch args[0] {
+	case "export":
+		return runEnrollmentsExport(args[1:])
+	default:
+		return fmt.Errorf("unknown enrollments command %q", args[0])
+	}
+}
+
+func runEnrollmentsExport(args []string) error {
+	fs := flag.NewFlagSet("enrollments export", flag.ContinueOnError)
+	formatFlag := fs.String("format", "csv", "output format: csv, json or md")
+	out := fs.String("out", "", "output file (defaults to stdout)")
+	state := fs.String("state", "", "only include enrollments in this state (active, invited, inactive, completed)")
+	sortBy := fs.String("sort", views.SortByScore, "sort order: score or name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+	if *sortBy != views.SortByScore && *sortBy != views.SortByName {
+		return fmt.Errorf("unknown sort %q (expected score or name)", *sortBy)
+	}
+
+	enrollments, err := newClient().GetCourseEnrollments()
+	if err != nil {
+		return err
+	}
+	students := views.OrderEnrollments(views.StudentEnrollments(enrollments), *state, *sortBy)
+
+	return writeOutput(*out, func(w io.Writer) error {
+		return export.WriteRoster(w, format, students)
+	})
+}
+
+func runFeedbackCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: feedback export [-out file] | feedback import <file>")
+	}
+
+	path := feedback.DefaultPath()
+	library, err := feedback.Load(path)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "export":
+		fs := flag.NewFlagSet("feedback export", flag.ContinueOnError)
+		out := fs.String("out", "", "output file (defaults to stdout)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return writeOutput(*out, library.Encode)
+	case "import":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: feedback import <file>")
+		}
+		file, err := os.Open(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", args[1], err)
+		}
+		defer file.Close()
+
+		imported, err := feedback.Decode(file)
+		if err != nil {
+			return err
+		}
+		added, updated := library.Merge(imported)
+		if err := library.Save(path); err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d templates (%d added, %d updated) into %s\n", len(imported.Templates), added, updated, path)
+		return nil
+	default:
+		return fmt.Errorf("unknown feedback command %q", args[0])
+	}
+}
+
+// runLintCommand lints every lesson in the course, or one module, and fails
+// when any rule reports an error so it can gate publishing in scripts.
+func runLintCommand(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	formatFlag := fs.String("format", "json", "output format: json, csv or md")
+	out := fs.String("out", "", "output file (defaults to stdout)")
+	moduleID := fs.Int("module", 0, "only lint the module with this id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	client := newClient()
+	modules, err := client.GetModules()
+	if err != nil {
+		return err
+	}
+
+	var findings []lint.Finding
+	for _, module := range modules {
+		if *moduleID != 0 && module.ID != *moduleID {
+			continue
+		}
+		nodes, err := client.GetModuleItems(module.ID)
+		if err != nil {
+			return err
+		}
+		moduleFindings, err := lint.LintModule(client, module, nodes)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, moduleFindings...)
+	}
+
+	if err := writeOutput(*out, func(w io.Writer) error {
+		return export.WriteFindings(w, format, findings)
+	}); err != nil {
+		return err
+	}
+
+	errors, warnings := lint.Count(findings)
+	if errors > 0 {
+		return fmt.Errorf("lint found %d errors and %d warnings", errors, warnings)
+	}
+	return nil
+}
+
+// runLinksCommand checks the links in one module, or every module, and writes
+// a report of the ones that aren't ok.
+func runLinksCommand(args []string) error {
+	fs := flag.NewFlagSet("links", flag.ContinueOnError)
+	formatFlag := fs.String("format", "csv", "output format: csv, json or md")
+	out := fs.String("out", "", "output file (defaults to stdout)")
+	moduleID := fs.Int("module", 0, "only check the module with this id")
+	all := fs.Bool("all", false, "include links that are ok")
+	concurrency := fs.Int("concurrency", linkcheck.DefaultConcurrency, "number of links to check at once")
+	timeout := fs.Duration("timeout", linkcheck.DefaultTimeout, "timeout for each link")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	client := newClient()
+	modules, err := client.GetModules()
+	if err != nil {
+		return err
+	}
+
+	var links []linkcheck.Link
+	for _, module := range modules {
+		if *moduleID != 0 && module.ID != *moduleID {
+			continue
+		}
+		nodes, err := client.GetModuleItems(module.ID)
+		if err != nil {
+			return err
+		}
+		moduleLinks, err := linkcheck.Collect(client, nodes)
+		if err != nil {
+			return err
+		}
+		links = append(links, moduleLinks...)
+	}
+
+	opts := linkcheck.Options{
+		Concurrency: *concurrency,
+		Timeout:     *timeout,
+	}
+	// Without a token, GitHub 404s can't be told apart from private repos
+	if gh, err := github.NewClientFromEnv(); err == nil {
+		opts.GitHub = gh
+	}
+	results := linkcheck.Check(context.Background(), links, opts)
+	if !*all {
+		results = linkcheck.Problems(results)
+	}
+
+	return writeOutput(*out, func(w io.Writer) error {
+		return export.WriteLinkReport(w, format, results)
+	})
+}
+
+func runGitHubCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: github sync [flags]")
+	}
+
+	switch args[0] {
+	case "sync":
+		return runGitHubSync(args[1:])
+	default:
+		return fmt.Errorf("unknown github command %q", args[0])
+	}
+}
+
+// runGitHubSync previews the changes that bring the cohort team in line with
+// the Canvas roster and applies them once confirmed.
+func runGitHubSync(args []string) error {
+	fs := flag.NewFlagSet("github sync", flag.ContinueOnError)
+	source := fs.String("source", roster.SourceFile, "where GitHub usernames come from: file or canvas")
+	mappingPath := fs.String("mapping", roster.DefaultPath(), "username mapping file for -source file")
+	column := fs.String("column", roster.DefaultColumn, "custom gradebook column for -source canvas")
+	dryRun := fs.Bool("dry-run", false, "only print the changes")
+	yes := fs.Bool("yes", false, "apply the changes without asking")
+	noRemove := fs.Bool("no-remove", false, "only invite, never remove team members")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	gh, err := github.NewClientFromEnv()
+	if err != nil {
+		return err
+	}
+	client := newClient()
+	team, err := client.GetGitHubTeam()
+	if err != nil {
+		return err
+	}
+	usernames, err := roster.LoadUsernames(client, *source, *mappingPath, *column)
+	if err != nil {
+		return err
+	}
+	plan, err := roster.BuildPlan(client, gh, team, usernames)
+	if err != nil {
+		return err
+	}
+
+	changes := plan.Changes
+	if *noRemove {
+		changes = nil
+		for _, change := range plan.Changes {
+			if change.Action == roster.ActionInvite {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	fmt.Printf("Team %s/%s: %d students in sync, %d without a GitHub username\n",
+		team.Organization, github.TeamSlug(team.Team), len(plan.InSync), len(plan.Unmapped))
+	for _, name := range plan.Unmapped {
+		fmt.Printf("  unmapped %s\n", name)
+	}
+	if len(plan.Held) > 0 {
+		fmt.Printf("Not removing %d members until every student has a GitHub username, since they may belong to the students above:\n", len(plan.Held))
+		for _, login := range plan.Held {
+			fmt.Printf("  held     %s\n", login)
+		}
+	}
+	for _, change := range changes {
+		fmt.Printf("  %-8s %-25s %s\n", change.Action, change.Username, change.Student)
+	}
+	if len(changes) == 0 {
+		fmt.Println("Nothing to change.")
+		return nil
+	}
+	if *dryRun {
+		fmt.Printf("Dry run: %d changes not applied.\n", len(changes))
+		return nil
+	}
+
+	if !*yes {
+		fmt.Printf("Apply %d changes? [y/N] ", len(changes))
+		var answer string
+		fmt.Scanln(&answer)
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	failed := 0
+	roster.Apply(gh, team, changes, func(change roster.Change, err error) {
+		if err != nil {
+			failed++
+			fmt.Printf("  failed   %-25s %v\n", change.Username, err)
+			return
+		}
+		fmt.Printf("  %-8s %s\n", change.Action, change.Username)
+	})
+	if failed > 0 {
+		return fmt.Errorf("%d of %d changes failed", failed, len(changes))
+	}
+	return nil
+}
+
+// runCloneCommand checks out every student's repository for an assignment
+// and reports failures and the latest commit per student.
+func runCloneCommand(args []string) error {
+	fs := flag.NewFlagSet("clone", flag.ContinueOnError)
+	assignmentID := fs.Int("assignment", 0, "assignment id (required)")
+	root := fs.String("dir", workspace.DefaultRoot, "workspace directory")
+	concurrency := fs.Int("concurrency", workspace.DefaultConcurrency, "number of repositories to clone at once")
+	timeout := fs.Duration("timeout", workspace.DefaultTimeout, "timeout for each clone or fetch")
+	forks := fs.String("forks", "", "find forks of the starter repository using GitHub usernames from file or canvas")
+	formatFlag := fs.String("format", "md", "report format: csv, json or md")
+	out := fs.String("out", "", "report file (defaults to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *assignmentID == 0 {
+		return fmt.Errorf("usage: clone -assignment <id> [flags]")
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	client := newClient()
+	assignment, err := client.GetAssignment(*assignmentID)
+	if err != nil {
+		return err
+	}
+	submissions, err := client.GetAssignmentSubmissions(*assignmentID)
+	if err != nil {
+		return err
+	}
+
+	var usernames map[int]string
+	if *forks != "" {
+		usernames, err = roster.LoadUsernames(client, *forks, roster.DefaultPath(), roster.DefaultColumn)
+		if err != nil {
+			return err
+		}
+	}
+
+	targets, unresolved := workspace.Resolve(*root, assignment, submissions, usernames)
+	fmt.Fprintf(os.Stderr, "Syncing %d repositories for %s into %s\n", len(targets), assignment.Name, workspace.AssignmentDir(*root, assignment))
+
+	results := workspace.Sync(context.Background(), targets, *concurrency, *timeout)
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	for _, name := range unresolved {
+		results = append(results, workspace.Result{
+			Target: workspace.Target{Student: name},
+			Error:  "no repository found",
+		})
+	}
+
+	if err := writeOutput(*out, func(w io.Writer) error {
+		return export.WriteCloneReport(w, format, results)
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d synced, %d failed, %d without a repository\n", len(targets)-failed, failed, len(unresolved))
+	return nil
+}
+
+func runAutogradeCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: autograde run -assignment <id> [flags] | autograde post -assignment <id> [-dry-run] <report.csv>")
+	}
+
+	switch args[0] {
+	case "run":
+		return runAutogradeRun(args[1:])
+	case "post":
+		return runAutogradePost(args[1:])
+	default:
+		return fmt.Errorf("unknown autograde command %q", args[0])
+	}
+}
+
+// runAutogradeRun tests every cloned repository for an assignment and writes
+// a report that can be reviewed and then posted.
+func runAutogradeRun(args []string) error {
+	fs := flag.NewFlagSet("autograde run", flag.ContinueOnError)
+	assignmentID := fs.Int("assignment", 0, "assignment id (required)")
+	root := fs.String("dir", workspace.DefaultRoot, "workspace directory the repositories were cloned into")
+	configPath := fs.String("config", "", "autograder config (defaults to autograde.yaml in the assignment directory)")
+	concurrency := fs.Int("concurrency", 1, "number of repositories to test at once")
+	formatFlag := fs.String("format", "csv", "report format: csv, json or md")
+	out := fs.String("out", "", "report file (defaults to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *assignmentID == 0 {
+		return fmt.Errorf("usage: autograde run -assignment <id> [flags]")
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	client := newClient()
+	assignment, err := client.GetAssignment(*assignmentID)
+	if err != nil {
+		return err
+	}
+	submissions, err := client.GetAssignmentSubmissions(*assignmentID)
+	if err != nil {
+		return err
+	}
+
+	dir := workspace.AssignmentDir(*root, assignment)
+	if *configPath == "" {
+		*configPath = autograde.DefaultConfigPath(dir)
+	}
+	config, err := autograde.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	cloned := workspace.CheckedOut(*root, assignment, submissions)
+	if len(cloned) == 0 {
+		return fmt.Errorf("no student repositories in %s; run clone first", dir)
+	}
+
+	fmt.Fprintf(os.Stderr, "Testing %d repositories for %s\n", len(cloned), assignment.Name)
+	results := autograde.Run(context.Background(), config, cloned, assignment.PointsPossible, *concurrency)
+
+	// Keep each run's output next to the repositories for review.
+	logDir := filepath.Join(dir, ".autograde")
+	logged := true
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to keep test output: %v\n", err)
+		logged = false
+	} else {
+		for _, r := range results {
+			path := filepath.Join(logDir, filepath.Base(r.Dir)+".log")
+			if err := os.WriteFile(path, []byte(r.Output), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to keep test output for %s: %v\n", r.Student, err)
+				logged = false
+			}
+		}
+	}
+
+	if err := writeOutput(*out, func(w io.Writer) error {
+		return export.WriteAutogradeReport(w, format, results)
+	}); err != nil {
+		return err
+	}
+	if logged {
+		fmt.Fprintf(os.Stderr, "Test output is in %s\n", logDir)
+	}
+	return nil
+}
+
+// runAutogradePost posts the scores from a reviewed autograder report.
+func runAutogradePost(args []string) error {
+	fs := flag.NewFlagSet("autograde post", flag.ContinueOnError)
+	assignmentID := fs.Int("assignment", 0, "assignment id (required)")
+	dryRun := fs.Bool("dry-run", false, "only print the scores that would be posted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *assignmentID == 0 || fs.NArg() != 1 {
+		return fmt.Errorf("usage: autograde post -assignment <id> [-dry-run] <report.csv>")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", fs.Arg(0), err)
+	}
+	defer file.Close()
+
+	client := newClient()
+	assignment, err := client.GetAssignment(*assignmentID)
+	if err != nil {
+		return err
+	}
+	drafts, err := autograde.ReadDraftScores(file, assignment.PointsPossible)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, draft := range drafts {
+		fmt.Printf("  %-25s %6.2f  %s\n", draft.Student, draft.Score, draft.Comment())
+		if *dryRun {
+			continue
+		}
+		score := draft.Score
+		if err := client.GradeSubmission(*assignmentID, draft.UserID, api.GradeSubmissionRequest{
+			Score:   &score,
+			Comment: draft.Comment(),
+		}); err != nil {
+			failed++
+			fmt.Printf("  failed to post score for %s: %v\n", draft.Student, err)
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: %d scores not posted.\n", len(drafts))
+		return nil
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scores failed to post", failed, len(drafts))
+	}
+	fmt.Printf("Posted %d scores.\n", len(drafts))
+	return nil
+}
+
+// runSimilarityCommand compares the cloned repositories for an assignment and
+// lists the pairs of students worth a closer look.
+func runSimilarityCommand(args []string) error {
+	fs := flag.NewFlagSet("similarity", flag.ContinueOnError)
+	assignmentID := fs.Int("assignment", 0, "assignment id (required)")
+	root := fs.String("dir", workspace.DefaultRoot, "workspace directory the repositories were cloned into")
+	starterDir := fs.String("starter", "", "starter code to ignore (defaults to cloning the repository the assignment links)")
+	threshold := fs.Float64("threshold", similarity.DefaultThreshold, "lowest similarity to report, from 0 to 1")
+	maxShare := fs.Float64("max-share", similarity.DefaultMaxShare, "ignore code shared by more than this fraction of students")
+	k := fs.Int("k", similarity.DefaultK, "tokens per fingerprint")
+	window := fs.Int("window", similarity.DefaultWindow, "winnowing window")
+	formatFlag := fs.String("format", "md", "report format: csv, json or md")
+	out := fs.String("out", "", "report file (defaults to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *assignmentID == 0 {
+		return fmt.Errorf("usage: similarity -assignment <id> [flags]")
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	client := newClient()
+	assignment, err := client.GetAssignment(*assignmentID)
+	if err != nil {
+		return err
+	}
+	submissions, err := client.GetAssignmentSubmissions(*assignmentID)
+	if err != nil {
+		return err
+	}
+
+	targets := workspace.CheckedOut(*root, assignment, submissions)
+	if len(targets) < 2 {
+		return fmt.Errorf("need at least two student repositories in %s; run clone first", workspace.AssignmentDir(*root, assignment))
+	}
+
+	if *starterDir == "" {
+		if repos := github.FindRepos(assignment.Description); len(repos) > 0 {
+			starter := workspace.Target{
+				Student: "starter",
+				RepoURL: repos[0].URL(),
+				Dir:     filepath.Join(workspace.AssignmentDir(*root, assignment), ".starter"),
+			}
+			result := workspace.Sync(context.Background(), []workspace.Target{starter}, 1, workspace.DefaultTimeout)[0]
+			if result.Error != "" {
+				fmt.Fprintf(os.Stderr, "Comparing without starter code: %s\n", result.Error)
+			} else {
+				*starterDir = starter.Dir
+			}
+		}
+	}
+
+	starter := similarity.Fingerprints{}
+	if *starterDir != "" {
+		if starter, err = similarity.FingerprintDir(*starterDir, *k, *window); err != nil {
+			return fmt.Errorf("failed to read starter code: %w", err)
+		}
+	}
+
+	var students []similarity.Student
+	for _, target := range targets {
+		fps, err := similarity.FingerprintDir(target.Dir, *k, *window)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", target.Dir, err)
+		}
+		students = append(students, similarity.Student{Name: target.Student, Fingerprints: fps})
+	}
+
+	pairs := similarity.Compare(students, starter, similarity.Options{Threshold: *threshold, MaxShare: *maxShare})
+	fmt.Fprintf(os.Stderr, "Compared %d students: %d pairs at or above %.0f%%\n", len(students), len(pairs), *threshold*100)
+	return writeOutput(*out, func(w io.Writer) error {
+		return export.WriteSimilarityReport(w, format, pairs)
+	})
+}
+
+// runGradebookCommand dispatches the gradebook export and import subcommands.
+func runGradebookCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: gradebook export|import [flags]")
+	}
+
+	switch args[0] {
+	case "export":
+		return runGradebookExport(args[1:])
+	case "import":
+		return runGradebookImport(args[1:])
+	default:
+		return fmt.Errorf("unknown gradebook command %q", args[0])
+	}
+}
+
+// runGradebookExport writes every student's scores as a CSV in the layout
+// Canvas exports, so it can be edited and brought back with import.
+func runGradebookExport(args []string) error {
+	fs := flag.NewFlagSet("gradebook export", flag.ContinueOnError)
+	out := fs.String("out", "", "output file (defaults to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	g, err := gradebook.Load(newClient())
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, func(w io.Writer) error {
+		return export.WriteGradebook(w, g)
+	})
+}
+
+// runGradebookImport checks a gradebook CSV against the course, prints the
+// scores that would change and posts them in batches once confirmed.
+func runGradebookImport(args []string) error {
+	fs := flag.NewFlagSet("gradebook import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only print the changes")
+	yes := fs.Bool("yes", false, "post the changes without asking")
+	batchSize := fs.Int("batch", 50, "grades per request")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: gradebook import [-dry-run] [-yes] [-batch n] <gradebook.csv>")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", fs.Arg(0), err)
+	}
+	defer file.Close()
+
+	sheet, err := gradebook.ReadSheet(file)
+	if err != nil {
+		return err
+	}
+	client := newClient()
+	g, err := gradebook.Load(client)
+	if err != nil {
+		return err
+	}
+
+	diff := gradebook.Compare(g, sheet)
+	for _, warning := range diff.Warnings {
+		fmt.Printf("  warning  %s\n", warning)
+	}
+	for _, problem := range diff.Problems {
+		fmt.Printf("  problem  %s\n", problem)
+	}
+	if len(diff.Problems) > 0 {
+		return fmt.Errorf("%d problems in %s, nothing posted", len(diff.Problems), fs.Arg(0))
+	}
+
+	fmt.Printf("%d scores changed, %d unchanged\n", len(diff.Changes), diff.Unchanged)
+	for _, change := range diff.Changes {
+		old := "-"
+		if change.Old != nil {
+			old = strconv.FormatFloat(*change.Old, 'f', -1, 64)
+		}
+		fmt.Printf("  %-25s %-30s %6s -> %s\n", change.Student, change.Assignment, old,
+			strconv.FormatFloat(change.New, 'f', -1, 64))
+	}
+	if len(diff.Changes) == 0 {
+		fmt.Println("Nothing to change.")
+		return nil
+	}
+	if *dryRun {
+		fmt.Printf("Dry run: %d scores not posted.\n", len(diff.Changes))
+		return nil
+	}
+
+	if !*yes {
+		fmt.Printf("Post %d scores? [y/N] ", len(diff.Changes))
+		var answer string
+		fmt.Scanln(&answer)
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	batches := gradebook.Batches(diff.Changes, *batchSize)
+	failed := 0
+	for i, batch := range batches {
+		progress, err := client.UpdateGrades(batch)
+		if err != nil {
+			failed += len(batch)
+			fmt.Printf("  batch %d of %d failed: %v\n", i+1, len(batches), err)
+			continue
+		}
+		fmt.Printf("  batch %d of %d: %d scores %s\n", i+1, len(batches), len(batch), progress.WorkflowState)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scores failed to post", failed, len(diff.Changes))
+	}
+	fmt.Printf("Posted %d scores. Canvas applies them in the background.\n", len(diff.Changes))
+	return nil
+}
+
+// runLateCommand previews late penalties for one assignment, or every
+// assignment anyone has a due date for, and applies them as grade changes
+// with a comment once confirmed.
+func runLateCommand(args []string) error {
+	fs := flag.NewFlagSet("late", flag.ContinueOnError)
+	assignmentID := fs.Int("assignment", 0, "assignment id (defaults to every assignment with a due date)")
+	policyPath := fs.String("policy", latepolicy.DefaultPath(), "late policy file")
+	formatFlag := fs.String("format", "md", "preview format: csv, json or md")
+	out := fs.String("out", "", "preview file (defaults to stdout)")
+	apply := fs.Bool("apply", false, "post the adjusted scores")
+	yes := fs.Bool("yes", false, "with -apply, post without asking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+	policy, err := latepolicy.Load(*policyPath)
+	if err != nil {
+		return err
+	}
+
+	client := newClient()
+	var assignments []api.Assignment
+	if *assignmentID != 0 {
+		assignment, err := client.GetAssignment(*assignmentID)
+		if err != nil {
+			return err
+		}
+		assignments = append(assignments, assignment)
+	} else if assignments, err = client.GetAssignments(); err != nil {
+		return err
+	}
+	overrides, err := client.GetOverrides()
+	if err != nil {
+		return err
+	}
+
+	var penalties []latepolicy.Penalty
+	applied, ungraded := 0, 0
+	for _, assignment := range assignments {
+		if *assignmentID == 0 && !latepolicy.HasDueDate(assignment, overrides) {
+			continue
+		}
+		submissions, err := client.GetAssignmentSubmissions(assignment.ID)
+		if err != nil {
+			return err
+		}
+		result := policy.Compute(assignment, submissions, overrides)
+		penalties = append(penalties, result.Penalties...)
+		applied += result.Applied
+		ungraded += result.Ungraded
+	}
+
+	if err := writeOutput(*out, func(w io.Writer) error {
+		return export.WriteLatePenalties(w, format, penalties)
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Policy: %s\n", policy)
+	fmt.Fprintf(os.Stderr, "%d penalties to apply, %d already applied, %d late but not graded yet\n", len(penalties), applied, ungraded)
+	if !*apply || len(penalties) == 0 {
+		return nil
+	}
+
+	if !*yes {
+		fmt.Fprintf(os.Stderr, "Apply %d penalties? [y/N] ", len(penalties))
+		var answer string
+		fmt.Scanln(&answer)
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, penalty := range penalties {
+		score := penalty.Adjusted
+		if err := client.GradeSubmission(penalty.AssignmentID, penalty.UserID, api.GradeSubmissionRequest{
+			Score:   &score,
+			Comment: penalty.Comment(),
+		}); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "  failed to apply penalty for %s on %s: %v\n", penalty.Student, penalty.Assignment, err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d penalties failed to apply", failed, len(penalties))
+	}
+	fmt.Fprintf(os.Stderr, "Applied %d penalties.\n", len(penalties))
+	return nil
+}
+
+// runOutlineCommand writes the course's modules, lessons and lesson items, or
+// a single module's, as a Markdown or JSON outline.
+func runOutlineCommand(args []string) error {
+	fs := flag.NewFlagSet("outline", flag.ContinueOnError)
+	formatFlag := fs.String("format", "md", "output format: md or json")
+	out := fs.String("out", "", "output file (defaults to stdout)")
+	moduleID := fs.Int("module", 0, "only export the module with this id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+	if format == export.FormatCSV {
+		return fmt.Errorf("the outline can't be exported as csv, use md or json")
+	}
+
+	client := newClient()
+	all, err := client.GetModules()
+	if err != nil {
+		return err
+	}
+
+	var modules []api.Module
+	lessons := map[int][]api.ModuleNode{}
+	for _, module := range all {
+		if *moduleID != 0 && module.ID != *moduleID {
+			continue
+		}
+		nodes, err := client.GetModuleItems(module.ID)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, module)
+		lessons[module.ID] = nodes
+	}
+	if *moduleID != 0 && len(modules) == 0 {
+		return fmt.Errorf("no module with id %d", *moduleID)
+	}
+
+	return writeOutput(*out, func(w io.Writer) error {
+		return export.WriteOutline(w, format, export.BuildOutline(modules, lessons))
+	})
+}
+
+func newClient() *api.Client {
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	return api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+}
+
+// writeOutput calls write with the named file, or stdout when path is empty.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := write(file); err != nil {
+		return err
+	}
+	return file.Close()
+}
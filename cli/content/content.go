@@ -0,0 +1,82 @@
+// Package content loads the assignments and pages behind module items, for
+// the checks that read their bodies.
+package content
+
+import (
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/markup"
+)
+
+// Source is the part of the API client needed to read item bodies.
+type Source interface {
+	GetAssignment(assignmentID int) (api.Assignment, error)
+	GetPage(pageURL string) (api.Page, error)
+}
+
+// Set holds the assignments and pages behind a set of module items.
+type Set struct {
+	Assignments map[int]api.Assignment
+	Pages       map[string]api.Page
+}
+
+// Fetch loads the assignment or page behind each item that has one.
+func Fetch(src Source, items []api.Lesson) (Set, error) {
+	s := Set{
+		Assignments: map[int]api.Assignment{},
+		Pages:       map[string]api.Page{},
+	}
+	for _, item := range items {
+		switch {
+		case item.Type == "Assignment" && item.ContentID != 0:
+			assignment, err := src.GetAssignment(item.ContentID)
+			if err != nil {
+				return Set{}, err
+			}
+			s.Assignments[item.ContentID] = assignment
+		case item.Type == "Page" && item.PageURL != "":
+			page, err := src.GetPage(item.PageURL)
+			if err != nil {
+				return Set{}, err
+			}
+			s.Pages[item.PageURL] = page
+		}
+	}
+	return s, nil
+}
+
+// Walk fetches the contents of each lesson in nodes and calls fn for the
+// lesson item and each of its children.
+func Walk(src Source, nodes []api.ModuleNode, fn func(s Set, item api.Lesson)) error {
+	for _, node := range nodes {
+		items := append([]api.Lesson{node.Lesson}, node.Children...)
+		s, err := Fetch(src, items)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			fn(s, item)
+		}
+	}
+	return nil
+}
+
+// Body returns the HTML body of a page or assignment item, if it was fetched.
+func (s Set) Body(item api.Lesson) string {
+	switch item.Type {
+	case "Assignment":
+		return s.Assignments[item.ContentID].Description
+	case "Page":
+		return s.Pages[item.PageURL].Body
+	}
+	return ""
+}
+
+// Links returns the URL of an ExternalUrl item followed by the links in the
+// item's body.
+func (s Set) Links(item api.Lesson) []string {
+	links := markup.Links(s.Body(item))
+	if item.Type == "ExternalUrl" && item.ExternalURL != "" {
+		links = append([]string{item.ExternalURL}, links...)
+	}
+	return links
+}
@@ -0,0 +1,69 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+)
+
+// CourseSource is the part of the API client needed to walk every module.
+type CourseSource interface {
+	Source
+	GetModules() ([]api.Module, error)
+	GetModuleItems(moduleId int) ([]api.ModuleNode, error)
+}
+
+// Repos returns the GitHub repositories the item links to, found the same
+// way publishing finds the repository to grant.
+func (s Set) Repos(item api.Lesson) []github.Repo {
+	if item.Type == "ExternalUrl" {
+		return github.FindRepos(item.ExternalURL)
+	}
+	return github.FindRepos(s.Body(item))
+}
+
+// CourseRepos returns every GitHub repository linked from an ExternalUrl item,
+// an assignment description or a page, with the items that link to it. Each
+// item is listed once however often it links to the repository.
+func CourseRepos(src CourseSource) ([]github.RepoRef, error) {
+	modules, err := src.GetModules()
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []github.RepoRef
+	index := map[string]int{}
+	add := func(repo github.Repo, source string) {
+		key := strings.ToLower(repo.String())
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(refs)
+			refs = append(refs, github.RepoRef{Repo: repo, Sources: []string{source}})
+			return
+		}
+		for _, s := range refs[i].Sources {
+			if s == source {
+				return
+			}
+		}
+		refs[i].Sources = append(refs[i].Sources, source)
+	}
+
+	for _, module := range modules {
+		nodes, err := src.GetModuleItems(module.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = Walk(src, nodes, func(s Set, item api.Lesson) {
+			for _, repo := range s.Repos(item) {
+				add(repo, fmt.Sprintf("%s / %s", module.Name, item.Title))
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,40 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command returns the command that opens path in the user's editor, taken
+// from $VISUAL or $EDITOR and falling back to vi. Editors configured with
+// arguments (e.g. "code --wait") are split on whitespace.
+func Command(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	parts := strings.Fields(editor)
+	return exec.Command(parts[0], append(parts[1:], path)...)
+}
+
+// WriteTemp writes content to a new temporary file and returns its path. The
+// pattern is passed to os.CreateTemp, so "announcement-*.md" keeps the
+// extension editors use for syntax highlighting.
+func WriteTemp(pattern string, content string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	return file.Name(), file.Close()
+}
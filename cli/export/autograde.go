@@ -0,0 +1,49 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/autograde"
+)
+
+// AutogradeHeader is also what autograde.ReadDraftScores expects back after
+// review.
+var AutogradeHeader = []string{"Student", "User ID", "Passed", "Failed", "Skipped", "Exit Code", "Duration", "Score", "Error"}
+
+// WriteAutogradeReport writes one row per student. Rows without a score are
+// left blank for the instructor to fill in.
+func WriteAutogradeReport(w io.Writer, format Format, results []autograde.Result) error {
+	var records [][]string
+	for _, r := range results {
+		score := ""
+		if r.Score != nil {
+			score = strconv.FormatFloat(*r.Score, 'f', -1, 64)
+		}
+		records = append(records, []string{
+			r.Student,
+			strconv.Itoa(r.UserID),
+			strconv.Itoa(r.Passed),
+			strconv.Itoa(r.Failed),
+			strconv.Itoa(r.Skipped),
+			strconv.Itoa(r.ExitCode),
+			r.Duration.String(),
+			score,
+			r.Error,
+		})
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, append([][]string{AutogradeHeader}, records...))
+	case FormatJSON:
+		if results == nil {
+			results = []autograde.Result{}
+		}
+		return writeJSON(w, results)
+	case FormatMarkdown:
+		return writeMarkdownTable(w, AutogradeHeader, records)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
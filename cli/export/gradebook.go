@@ -0,0 +1,41 @@
+package export
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/gradebook"
+)
+
+// WriteGradebook writes the gradebook as CSV in the layout Canvas exports
+// and imports: the student columns, one "Name (id)" column per assignment,
+// a points possible row and the student's current score at the end.
+func WriteGradebook(w io.Writer, g *gradebook.Gradebook) error {
+	header := append([]string{}, gradebook.StudentColumns...)
+	points := []string{gradebook.PointsPossibleLabel, "", "", "", ""}
+	for _, a := range g.Assignments {
+		header = append(header, gradebook.ColumnName(a))
+		points = append(points, strconv.FormatFloat(a.PointsPossible, 'f', -1, 64))
+	}
+	header = append(header, "Current Score")
+	points = append(points, "")
+
+	records := [][]string{header, points}
+	for _, student := range g.Students {
+		record := []string{student.User.Name, strconv.Itoa(student.User.ID), "", "", ""}
+		for _, a := range g.Assignments {
+			submission, _ := g.Submission(student.User.ID, a.ID)
+			switch {
+			case submission.Excused:
+				record = append(record, gradebook.Excused)
+			case submission.Score != nil:
+				record = append(record, strconv.FormatFloat(*submission.Score, 'f', -1, 64))
+			default:
+				record = append(record, "")
+			}
+		}
+		record = append(record, strconv.FormatFloat(float64(student.Grades.Score), 'f', 2, 32))
+		records = append(records, record)
+	}
+	return writeCSV(w, records)
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/latepolicy"
+)
+
+var latePenaltyHeader = []string{"Student", "Assignment", "Due", "Submitted", "Days Late", "Penalty", "Score", "Adjusted"}
+
+// WriteLatePenalties writes the previewed penalties, one row per late
+// submission.
+func WriteLatePenalties(w io.Writer, format Format, penalties []latepolicy.Penalty) error {
+	var records [][]string
+	for _, p := range penalties {
+		due := p.DueAt.Local().Format(time.DateTime)
+		if p.Extended {
+			due += " (extended)"
+		}
+		records = append(records, []string{
+			p.Student,
+			p.Assignment,
+			due,
+			p.SubmittedAt.Local().Format(time.DateTime),
+			strconv.Itoa(p.DaysLate),
+			strconv.FormatFloat(p.Percent, 'f', -1, 64) + "%",
+			strconv.FormatFloat(p.Score, 'f', -1, 64),
+			strconv.FormatFloat(p.Adjusted, 'f', -1, 64),
+		})
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, append([][]string{latePenaltyHeader}, records...))
+	case FormatJSON:
+		if penalties == nil {
+			penalties = []latepolicy.Penalty{}
+		}
+		return writeJSON(w, penalties)
+	case FormatMarkdown:
+		return writeMarkdownTable(w, latePenaltyHeader, records)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/linkcheck"
+)
+
+var linkReportHeader = []string{"Status", "Code", "URL", "Location", "Error", "Found In"}
+
+// WriteLinkReport writes link check results in the order given.
+func WriteLinkReport(w io.Writer, format Format, results []linkcheck.Result) error {
+	var records [][]string
+	for _, r := range results {
+		code := ""
+		if r.StatusCode != 0 {
+			code = strconv.Itoa(r.StatusCode)
+		}
+		records = append(records, []string{
+			string(r.Status),
+			code,
+			r.URL,
+			r.Location,
+			r.Error,
+			strings.Join(r.Sources, "; "),
+		})
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, append([][]string{linkReportHeader}, records...))
+	case FormatJSON:
+		if results == nil {
+			results = []linkcheck.Result{}
+		}
+		return writeJSON(w, results)
+	case FormatMarkdown:
+		return writeMarkdownTable(w, linkReportHeader, records)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/lint"
+)
+
+var findingsHeader = []string{"Severity", "Rule", "Module ID", "Module", "Lesson", "Item ID", "Item", "Message"}
+
+// WriteFindings writes lint findings. JSON output is always an array, even
+// when the course is clean, so scripts can rely on its shape.
+func WriteFindings(w io.Writer, format Format, findings []lint.Finding) error {
+	var records [][]string
+	for _, f := range findings {
+		records = append(records, []string{
+			string(f.Severity),
+			f.Rule,
+			strconv.Itoa(f.ModuleID),
+			f.Module,
+			f.Lesson,
+			strconv.Itoa(f.ItemID),
+			f.Item,
+			f.Message,
+		})
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, append([][]string{findingsHeader}, records...))
+	case FormatJSON:
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		return writeJSON(w, findings)
+	case FormatMarkdown:
+		return writeMarkdownTable(w, findingsHeader, records)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
@@ -0,0 +1,155 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+// Outline is the course's modules, the lessons in each and the items under
+// each lesson, in course order.
+type Outline struct {
+	Modules []OutlineModule `json:"modules"`
+}
+
+type OutlineModule struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	Published bool            `json:"published"`
+	UnlockAt  *time.Time      `json:"unlock_at,omitempty"`
+	Lessons   []OutlineLesson `json:"lessons"`
+}
+
+type OutlineLesson struct {
+	OutlineItem
+	Children []OutlineItem `json:"children"`
+}
+
+type OutlineItem struct {
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	Type      string     `json:"type"`
+	Published bool       `json:"published"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+	URL       string     `json:"url,omitempty"`
+}
+
+// BuildOutline puts each module's lessons, keyed by module id, under it.
+func BuildOutline(modules []api.Module, lessons map[int][]api.ModuleNode) Outline {
+	outline := Outline{Modules: []OutlineModule{}}
+	for _, m := range modules {
+		module := OutlineModule{
+			ID:        m.ID,
+			Name:      m.Name,
+			Published: m.Published,
+			UnlockAt:  m.UnlockAt,
+			Lessons:   []OutlineLesson{},
+		}
+		for _, node := range lessons[m.ID] {
+			lesson := OutlineLesson{OutlineItem: outlineItem(node.Lesson), Children: []OutlineItem{}}
+			for _, child := range node.Children {
+				lesson.Children = append(lesson.Children, outlineItem(child))
+			}
+			module.Lessons = append(module.Lessons, lesson)
+		}
+		outline.Modules = append(outline.Modules, module)
+	}
+	return outline
+}
+
+func outlineItem(l api.Lesson) OutlineItem {
+	item := OutlineItem{
+		ID:        l.ID,
+		Title:     l.Title,
+		Type:      l.Type,
+		Published: l.Published,
+		URL:       l.HtmlURL,
+	}
+	switch l.Type {
+	case "ExternalUrl":
+		item.URL = l.ExternalURL
+	case "SubHeader":
+		item.URL = ""
+	}
+	if l.ContentDetails != nil {
+		item.DueAt = l.ContentDetails.DueAt
+	}
+	return item
+}
+
+// WriteOutline writes the outline as nested Markdown headings and lists, or
+// as JSON. It has no CSV form.
+func WriteOutline(w io.Writer, format Format, outline Outline) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, outline)
+	case FormatMarkdown:
+		return writeMarkdownOutline(w, outline)
+	}
+	return fmt.Errorf("unsupported outline format %q, use md or json", format)
+}
+
+func writeMarkdownOutline(w io.Writer, outline Outline) error {
+	var b strings.Builder
+	b.WriteString("# Course Outline\n")
+
+	for i, module := range outline.Modules {
+		fmt.Fprintf(&b, "\n## %d. %s\n", i+1, markdownText(module.Name))
+		var notes []string
+		if !module.Published {
+			notes = append(notes, "Unpublished")
+		}
+		if module.UnlockAt != nil {
+			notes = append(notes, "Unlocks "+module.UnlockAt.Local().Format(outlineTimeFormat))
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, "\n_%s_\n", strings.Join(notes, " · "))
+		}
+		if len(module.Lessons) == 0 {
+			b.WriteString("\nNo lessons.\n")
+		}
+
+		for _, lesson := range module.Lessons {
+			fmt.Fprintf(&b, "\n### %s\n\n", outlineEntry(lesson.OutlineItem))
+			if len(lesson.Children) == 0 {
+				b.WriteString("No items.\n")
+			}
+			for _, child := range lesson.Children {
+				fmt.Fprintf(&b, "- %s\n", outlineEntry(child))
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write outline: %w", err)
+	}
+	return nil
+}
+
+const outlineTimeFormat = "2006-01-02 15:04"
+
+// outlineEntry is the item's title, linked when it has a URL, followed by
+// its type, due date and whether it's unpublished.
+func outlineEntry(item OutlineItem) string {
+	title := markdownText(item.Title)
+	if item.URL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, item.URL)
+	}
+	details := []string{item.Type}
+	if item.DueAt != nil {
+		details = append(details, "due "+item.DueAt.Local().Format(outlineTimeFormat))
+	}
+	if !item.Published {
+		details = append(details, "unpublished")
+	}
+	return fmt.Sprintf("%s (%s)", title, strings.Join(details, ", "))
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`")
+
+func markdownText(s string) string {
+	return markdownEscaper.Replace(s)
+}
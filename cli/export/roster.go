@@ -0,0 +1,141 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "md"
+)
+
+var Formats = []Format{FormatCSV, FormatJSON, FormatMarkdown}
+
+// ParseFormat accepts the file extension style names as well as "markdown".
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (expected csv, json or md)", s)
+}
+
+func (f Format) Label() string {
+	switch f {
+	case FormatCSV:
+		return "CSV"
+	case FormatJSON:
+		return "JSON"
+	case FormatMarkdown:
+		return "Markdown"
+	}
+	return string(f)
+}
+
+type RosterRow struct {
+	Name      string  `json:"name"`
+	ID        int     `json:"id"`
+	State     string  `json:"state"`
+	Score     float32 `json:"current_score"`
+	GradesURL string  `json:"grades_url"`
+}
+
+var rosterHeader = []string{"Name", "ID", "State", "Current Score", "Grades URL"}
+
+func NewRosterRows(enrollments []api.Enrollment) []RosterRow {
+	rows := make([]RosterRow, 0, len(enrollments))
+	for _, e := range enrollments {
+		rows = append(rows, RosterRow{
+			Name:      e.User.Name,
+			ID:        e.User.ID,
+			State:     e.State,
+			Score:     e.Grades.Score,
+			GradesURL: e.Grades.Url,
+		})
+	}
+	return rows
+}
+
+// WriteRoster writes the enrollments in the order given, so callers are
+// responsible for any filtering and sorting.
+func WriteRoster(w io.Writer, format Format, enrollments []api.Enrollment) error {
+	rows := NewRosterRows(enrollments)
+
+	switch format {
+	case FormatCSV:
+		records := [][]string{rosterHeader}
+		for _, r := range rows {
+			records = append(records, r.fields())
+		}
+		return writeCSV(w, records)
+	case FormatJSON:
+		return writeJSON(w, rows)
+	case FormatMarkdown:
+		var records [][]string
+		for _, r := range rows {
+			records = append(records, r.fields())
+		}
+		return writeMarkdownTable(w, rosterHeader, records)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+func (r RosterRow) fields() []string {
+	return []string{
+		r.Name,
+		strconv.Itoa(r.ID),
+		r.State,
+		strconv.FormatFloat(float64(r.Score), 'f', 1, 32),
+		r.GradesURL,
+	}
+}
+
+func writeCSV(w io.Writer, records [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+func writeMarkdownTable(w io.Writer, header []string, records [][]string) error {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(escapeCells(header), " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, record := range records {
+		b.WriteString("| " + strings.Join(escapeCells(record), " | ") + " |\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = strings.ReplaceAll(c, "|", `\|`)
+	}
+	return escaped
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/similarity"
+)
+
+var similarityHeader = []string{"Student A", "Student B", "Similarity", "Shared", "File A", "Lines A", "File B", "Lines B"}
+
+// WriteSimilarityReport writes one row per matching range, so a pair with
+// several copied sections spans several rows.
+func WriteSimilarityReport(w io.Writer, format Format, pairs []similarity.Pair) error {
+	var records [][]string
+	for _, p := range pairs {
+		for _, m := range p.Matches {
+			records = append(records, []string{
+				p.A,
+				p.B,
+				fmt.Sprintf("%.0f%%", p.Similarity*100),
+				strconv.Itoa(p.Shared),
+				m.A.File,
+				lineRange(m.A),
+				m.B.File,
+				lineRange(m.B),
+			})
+		}
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, append([][]string{similarityHeader}, records...))
+	case FormatJSON:
+		if pairs == nil {
+			pairs = []similarity.Pair{}
+		}
+		return writeJSON(w, pairs)
+	case FormatMarkdown:
+		return writeMarkdownTable(w, similarityHeader, records)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+func lineRange(s similarity.Span) string {
+	if s.StartLine == s.EndLine {
+		return strconv.Itoa(s.StartLine)
+	}
+	return fmt.Sprintf("%d-%d", s.StartLine, s.EndLine)
+}
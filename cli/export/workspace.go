@@ -0,0 +1,37 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/workspace"
+)
+
+var cloneReportHeader = []string{"Student", "Repository", "Source", "Action", "Last Commit", "Error"}
+
+// WriteCloneReport writes one row per student repository. Unresolved
+// students are expected as results with an error and no repository.
+func WriteCloneReport(w io.Writer, format Format, results []workspace.Result) error {
+	var records [][]string
+	for _, r := range results {
+		lastCommit := ""
+		if r.LastCommit != nil {
+			lastCommit = r.LastCommit.Local().Format(time.DateTime)
+		}
+		records = append(records, []string{r.Student, r.RepoURL, r.Source, r.Action, lastCommit, r.Error})
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, append([][]string{cloneReportHeader}, records...))
+	case FormatJSON:
+		if results == nil {
+			results = []workspace.Result{}
+		}
+		return writeJSON(w, results)
+	case FormatMarkdown:
+		return writeMarkdownTable(w, cloneReportHeader, records)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
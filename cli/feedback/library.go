@@ -0,0 +1,273 @@
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the library file format version. Bump it when the shape
+// of Library changes and teach Load how to upgrade older files.
+const CurrentVersion = 1
+
+// MaxHistory is how many earlier versions of each template are kept.
+const MaxHistory = 10
+
+// Template is a comment with placeholders. Each change to its body is a new
+// version, and the bodies it replaced are kept in History, oldest first.
+type Template struct {
+	Name      string     `yaml:"name"`
+	Body      string     `yaml:"body"`
+	Version   int        `yaml:"version,omitempty"`
+	UpdatedAt time.Time  `yaml:"updated_at,omitempty"`
+	History   []Revision `yaml:"history,omitempty"`
+}
+
+// Revision is an earlier version of a template's body.
+type Revision struct {
+	Version   int       `yaml:"version"`
+	Body      string    `yaml:"body"`
+	UpdatedAt time.Time `yaml:"updated_at,omitempty"`
+}
+
+type Library struct {
+	Version   int        `yaml:"version"`
+	Templates []Template `yaml:"templates"`
+}
+
+// Vars are the values substituted for the placeholders in a template body.
+type Vars struct {
+	FirstName      string
+	AssignmentName string
+	DueDate        *time.Time
+}
+
+// Placeholders lists the supported placeholders, for display in the views.
+var Placeholders = []string{"{{first_name}}", "{{assignment_name}}", "{{due_date}}"}
+
+func DefaultLibrary() *Library {
+	return &Library{
+		Version: CurrentVersion,
+		Templates: []Template{
+			{Name: "Great work", Body: "Great work on {{assignment_name}}, {{first_name}}!", Version: 1},
+			{Name: "Push commit", Body: "Hi {{first_name}}, please push your latest commit.", Version: 1},
+			{Name: "Missing README", Body: "Hi {{first_name}}, your submission is missing a README.", Version: 1},
+			{Name: "Failing tests", Body: "Tests are failing, please take another look before {{due_date}}.", Version: 1},
+		},
+	}
+}
+
+// DefaultPath is where the library lives unless FEEDBACK_LIBRARY is set.
+func DefaultPath() string {
+	if path := os.Getenv("FEEDBACK_LIBRARY"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "canvasInstructor", "feedback.yaml")
+}
+
+// Load reads the library at path, starting from the default templates when
+// the file does not exist yet.
+func Load(path string) (*Library, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultLibrary(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open feedback library: %w", err)
+	}
+	defer file.Close()
+
+	return Decode(file)
+}
+
+// Save writes the library to a temporary file next to path and renames it
+// into place, so a failed write leaves the previous library intact.
+func (l *Library) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create feedback library directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, ".feedback-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create feedback library: %w", err)
+	}
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to create feedback library: %w", err)
+	}
+	if err := l.Encode(file); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to write feedback library: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to replace feedback library: %w", err)
+	}
+	return nil
+}
+
+func Decode(r io.Reader) (*Library, error) {
+	var library Library
+	if err := yaml.NewDecoder(r).Decode(&library); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode feedback library: %w", err)
+	}
+
+	if library.Version != CurrentVersion {
+		return nil, fmt.Errorf("unsupported feedback library version %d, expected %d", library.Version, CurrentVersion)
+	}
+
+	for i, t := range library.Templates {
+		if strings.TrimSpace(t.Name) == "" {
+			return nil, fmt.Errorf("feedback template %d has no name", i+1)
+		}
+	}
+	return &library, nil
+}
+
+func (l *Library) Encode(w io.Writer) error {
+	l.Version = CurrentVersion
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return fmt.Errorf("failed to encode feedback library: %w", err)
+	}
+	return enc.Close()
+}
+
+// Merge adds the templates from other and updates any with the same name,
+// returning how many were added and updated. An imported template with a
+// later version replaces ours along with its history; one with a different
+// body but no later version becomes our next version.
+func (l *Library) Merge(other *Library) (added int, updated int) {
+	now := time.Now()
+	for _, t := range other.Templates {
+		i := l.index(t.Name)
+		switch {
+		case i < 0:
+			if t.Version == 0 {
+				t.Version = 1
+			}
+			l.Templates = append(l.Templates, t)
+			added++
+		case t.Version > l.Templates[i].Version:
+			l.Templates[i] = t
+			updated++
+		case t.Body != l.Templates[i].Body:
+			l.Templates[i] = l.Templates[i].revise(t.Body, now)
+			updated++
+		}
+	}
+	return added, updated
+}
+
+// Put adds t, or updates the template called previousName when it exists.
+// Changing the body makes a new version; renaming alone doesn't.
+func (l *Library) Put(previousName string, t Template) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if i := l.index(t.Name); i >= 0 && t.Name != previousName {
+		return fmt.Errorf("a template named %q already exists", t.Name)
+	}
+
+	now := time.Now()
+	if i := l.index(previousName); i >= 0 {
+		current := l.Templates[i]
+		if t.Body != current.Body {
+			current = current.revise(t.Body, now)
+		}
+		current.Name = t.Name
+		l.Templates[i] = current
+		return nil
+	}
+	l.Templates = append(l.Templates, Template{Name: t.Name, Body: t.Body, Version: 1, UpdatedAt: now})
+	return nil
+}
+
+// Restore makes an earlier version's body the template's next version.
+func (l *Library) Restore(name string, version int) error {
+	i := l.index(name)
+	if i < 0 {
+		return fmt.Errorf("no template named %q", name)
+	}
+	for _, r := range l.Templates[i].History {
+		if r.Version == version {
+			l.Templates[i] = l.Templates[i].revise(r.Body, time.Now())
+			return nil
+		}
+	}
+	return fmt.Errorf("template %q has no version %d", name, version)
+}
+
+// revise returns t with body as its next version, keeping the current body
+// in its history.
+func (t Template) revise(body string, now time.Time) Template {
+	history := append([]Revision{}, t.History...)
+	history = append(history, Revision{Version: t.Version, Body: t.Body, UpdatedAt: t.UpdatedAt})
+	if len(history) > MaxHistory {
+		history = history[len(history)-MaxHistory:]
+	}
+	t.History = history
+	t.Body = body
+	t.Version++
+	t.UpdatedAt = now
+	return t
+}
+
+func (l *Library) Remove(name string) {
+	if i := l.index(name); i >= 0 {
+		l.Templates = append(l.Templates[:i], l.Templates[i+1:]...)
+	}
+}
+
+func (l *Library) index(name string) int {
+	for i, t := range l.Templates {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t Template) Render(vars Vars) string {
+	dueDate := "the due date"
+	if vars.DueDate != nil {
+		dueDate = vars.DueDate.Local().Format("Mon Jan 2")
+	}
+	firstName := vars.FirstName
+	if firstName == "" {
+		firstName = "there"
+	}
+
+	return strings.NewReplacer(
+		"{{first_name}}", firstName,
+		"{{assignment_name}}", vars.AssignmentName,
+		"{{due_date}}", dueDate,
+	).Replace(t.Body)
+}
+
+// FirstName takes the first word of a display name.
+func FirstName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,65 @@
+package feedback
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageTemplate is a starting point for a Canvas inbox message. Unlike
+// comment templates these are addressed to a student rather than a
+// submission, so they know about the student's missing work instead.
+type MessageTemplate struct {
+	Name    string
+	Subject string
+	Body    string
+}
+
+type MessageVars struct {
+	FirstName    string
+	CurrentScore float32
+	Missing      []string
+}
+
+var MessagePlaceholders = []string{"{{first_name}}", "{{current_score}}", "{{missing_count}}", "{{missing_assignments}}"}
+
+var MessageTemplates = []MessageTemplate{
+	{
+		Name:    "Missing work",
+		Subject: "Missing assignments",
+		Body: "Hi {{first_name}},\n\n" +
+			"You currently have {{missing_count}} missing assignment(s):\n\n" +
+			"{{missing_assignments}}\n\n" +
+			"Please submit them as soon as you can, and reach out if you need help.",
+	},
+	{
+		Name:    "Checking in",
+		Subject: "Checking in",
+		Body: "Hi {{first_name}},\n\n" +
+			"Your current score is {{current_score}}. " +
+			"Let's find some time to talk about how things are going.",
+	},
+	{
+		Name:    "Blank",
+		Subject: "",
+		Body:    "Hi {{first_name}},\n\n",
+	},
+}
+
+func RenderMessage(text string, vars MessageVars) string {
+	firstName := vars.FirstName
+	if firstName == "" {
+		firstName = "there"
+	}
+
+	missing := "- (none)"
+	if len(vars.Missing) > 0 {
+		missing = "- " + strings.Join(vars.Missing, "\n- ")
+	}
+
+	return strings.NewReplacer(
+		"{{first_name}}", firstName,
+		"{{current_score}}", fmt.Sprintf("%.1f%%", vars.CurrentScore),
+		"{{missing_count}}", fmt.Sprint(len(vars.Missing)),
+		"{{missing_assignments}}", missing,
+	).Replace(text)
+}
@@ -0,0 +1,99 @@
+package github
+
+import (
+	"errors"
+	"sync"
+)
+
+type AccessStatus string
+
+const (
+	AccessGranted AccessStatus = "granted"
+	AccessPublic  AccessStatus = "public"
+	AccessNone    AccessStatus = "no access"
+	AccessMissing AccessStatus = "missing"
+	AccessError   AccessStatus = "error"
+)
+
+// RepoRef is a repository and the places in the course that link to it.
+type RepoRef struct {
+	Repo    Repo     `json:"repo"`
+	Sources []string `json:"sources"`
+}
+
+// Access is what a team can do with one repository.
+type Access struct {
+	RepoRef
+	Exists     bool   `json:"exists"`
+	Private    bool   `json:"private"`
+	Archived   bool   `json:"archived"`
+	Permission string `json:"permission,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Status summarises the access. Public repositories are readable without a
+// grant, so missing team access only matters for private ones.
+func (a Access) Status() AccessStatus {
+	switch {
+	case a.Error != "":
+		return AccessError
+	case !a.Exists:
+		return AccessMissing
+	case a.Permission != "":
+		return AccessGranted
+	case !a.Private:
+		return AccessPublic
+	}
+	return AccessNone
+}
+
+// Audit checks the team's access to every repository, at most concurrency
+// at a time, and returns the results in the same order as refs.
+func (c *Client) Audit(org string, team string, refs []RepoRef, concurrency int) []Access {
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	results := make([]Access, len(refs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = c.CheckAccess(org, team, ref)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// CheckAccess looks up one repository and the team's permission on it.
+func (c *Client) CheckAccess(org string, team string, ref RepoRef) Access {
+	access := Access{RepoRef: ref}
+
+	repository, err := c.GetRepository(ref.Repo)
+	if errors.Is(err, ErrNotFound) {
+		return access
+	}
+	if err != nil {
+		access.Error = err.Error()
+		return access
+	}
+	access.Exists = true
+	access.Private = repository.Private
+	access.Archived = repository.Archived
+
+	teamRepository, err := c.GetTeamRepository(org, team, ref.Repo)
+	if errors.Is(err, ErrNotFound) {
+		return access
+	}
+	if err != nil {
+		access.Error = err.Error()
+		return access
+	}
+	access.Permission = teamRepository.Permission()
+	return access
+}
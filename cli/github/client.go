@@ -0,0 +1,290 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const DefaultBaseURL = "https://api.github.com"
+
+// ErrNotFound is returned when GitHub answers 404, which it also does for
+// private resources the token can't see.
+var ErrNotFound = errors.New("not found")
+
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+	log     *slog.Logger
+}
+
+func NewClient(baseURL string, token string) *Client {
+	log := logger.With("component", "github_client", "base_url", baseURL)
+	log.Info("Creating new GitHub client")
+
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		client:  &http.Client{},
+		log:     log,
+	}
+}
+
+// NewClientFromEnv uses the GITHUB_TOKEN the API server is configured with.
+// GITHUB_API_URL overrides the API location for GitHub Enterprise.
+func NewClientFromEnv() (*Client, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+	baseURL := os.Getenv("GITHUB_API_URL")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return NewClient(strings.TrimSuffix(baseURL, "/"), token), nil
+}
+
+type Repository struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Private  bool   `json:"private"`
+	Archived bool   `json:"archived"`
+	HtmlURL  string `json:"html_url"`
+	// Permissions is only set on team repository responses.
+	Permissions map[string]bool `json:"permissions"`
+}
+
+// Permission returns the strongest permission in r.Permissions.
+func (r Repository) Permission() string {
+	for _, p := range []string{"admin", "maintain", "push", "triage", "pull"} {
+		if r.Permissions[p] {
+			return p
+		}
+	}
+	return ""
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// TeamSlug converts a team name to the slug GitHub uses in URLs, e.g.
+// "How to be a Wolf 101" becomes "how-to-be-a-wolf-101".
+func TeamSlug(name string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func (c *Client) GetRepository(repo Repo) (Repository, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, repo.Owner, repo.Name)
+	log := c.log.With("action", "get_repository", "url", url, "repository", repo.String())
+	log.Info("Fetching repository")
+
+	var repository Repository
+	if err := c.do(log, http.MethodGet, url, nil, http.StatusOK, &repository); err != nil {
+		return Repository{}, fmt.Errorf("failed to fetch repository %s: %w", repo, err)
+	}
+
+	log.Info("Successfully fetched repository")
+	return repository, nil
+}
+
+// GetTeamRepository returns the repository with the team's permissions, or
+// ErrNotFound when the team has no access.
+func (c *Client) GetTeamRepository(org string, team string, repo Repo) (Repository, error) {
+	url := fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", c.baseURL, org, TeamSlug(team), repo.Owner, repo.Name)
+	log := c.log.With("action", "get_team_repository", "url", url, "repository", repo.String())
+	log.Info("Fetching team repository access")
+
+	var repository Repository
+	err := c.do(log, http.MethodGet, url, nil, http.StatusOK, &repository,
+		"application/vnd.github.v3.repository+json")
+	if err != nil {
+		return Repository{}, fmt.Errorf("failed to fetch team access to %s: %w", repo, err)
+	}
+
+	log.Info("Successfully fetched team repository access", "permission", repository.Permission())
+	return repository, nil
+}
+
+// AddTeamRepository grants the team permission on the repository, the same
+// call the API server makes with "pull" when a lesson is published.
+func (c *Client) AddTeamRepository(org string, team string, repo Repo, permission string) error {
+	url := fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", c.baseURL, org, TeamSlug(team), repo.Owner, repo.Name)
+	log := c.log.With("action", "add_team_repository", "url", url, "repository", repo.String(), "permission", permission)
+	log.Info("Granting team repository access")
+
+	body := map[string]string{"permission": permission}
+	if err := c.do(log, http.MethodPut, url, body, http.StatusNoContent, nil); err != nil {
+		return fmt.Errorf("failed to grant access to %s: %w", repo, err)
+	}
+
+	log.Info("Successfully granted team repository access")
+	return nil
+}
+
+func (c *Client) RemoveTeamRepository(org string, team string, repo Repo) error {
+	url := fmt.Sprintf("%s/orgs/%s/teams/%s/repos/%s/%s", c.baseURL, org, TeamSlug(team), repo.Owner, repo.Name)
+	log := c.log.With("action", "remove_team_repository", "url", url, "repository", repo.String())
+	log.Info("Revoking team repository access")
+
+	if err := c.do(log, http.MethodDelete, url, nil, http.StatusNoContent, nil); err != nil {
+		return fmt.Errorf("failed to revoke access to %s: %w", repo, err)
+	}
+
+	log.Info("Successfully revoked team repository access")
+	return nil
+}
+
+type Member struct {
+	Login string `json:"login"`
+}
+
+// ListTeamMembers returns the team's members with the given role, "member"
+// or "maintainer", or everyone when role is empty.
+func (c *Client) ListTeamMembers(org string, team string, role string) ([]string, error) {
+	var logins []string
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/orgs/%s/teams/%s/members?per_page=100&page=%d", c.baseURL, org, TeamSlug(team), page)
+		if role != "" {
+			url += "&role=" + role
+		}
+		log := c.log.With("action", "list_team_members", "url", url)
+		log.Info("Fetching team members")
+
+		var members []Member
+		if err := c.do(log, http.MethodGet, url, nil, http.StatusOK, &members); err != nil {
+			return nil, fmt.Errorf("failed to fetch members of %s: %w", team, err)
+		}
+		for _, m := range members {
+			logins = append(logins, m.Login)
+		}
+		if len(members) < 100 {
+			break
+		}
+	}
+
+	c.log.Info("Successfully fetched team members", "team", team, "count", len(logins))
+	return logins, nil
+}
+
+// ListTeamInvitations returns the logins with a pending invitation to the
+// team. Invitations sent by email alone have no login and are skipped.
+func (c *Client) ListTeamInvitations(org string, team string) ([]string, error) {
+	var logins []string
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/orgs/%s/teams/%s/invitations?per_page=100&page=%d", c.baseURL, org, TeamSlug(team), page)
+		log := c.log.With("action", "list_team_invitations", "url", url)
+		log.Info("Fetching team invitations")
+
+		var invitations []struct {
+			Login string `json:"login"`
+		}
+		if err := c.do(log, http.MethodGet, url, nil, http.StatusOK, &invitations); err != nil {
+			return nil, fmt.Errorf("failed to fetch invitations to %s: %w", team, err)
+		}
+		for _, invitation := range invitations {
+			if invitation.Login != "" {
+				logins = append(logins, invitation.Login)
+			}
+		}
+		if len(invitations) < 100 {
+			break
+		}
+	}
+
+	c.log.Info("Successfully fetched team invitations", "team", team, "count", len(logins))
+	return logins, nil
+}
+
+// AddTeamMember adds the user to the team, inviting them to the organization
+// first if they are not already in it.
+func (c *Client) AddTeamMember(org string, team string, username string) error {
+	url := fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", c.baseURL, org, TeamSlug(team), username)
+	log := c.log.With("action", "add_team_member", "url", url, "username", username)
+	log.Info("Adding team member")
+
+	if err := c.do(log, http.MethodPut, url, map[string]string{"role": "member"}, http.StatusOK, nil); err != nil {
+		return fmt.Errorf("failed to add %s to %s: %w", username, team, err)
+	}
+
+	log.Info("Successfully added team member")
+	return nil
+}
+
+func (c *Client) RemoveTeamMember(org string, team string, username string) error {
+	url := fmt.Sprintf("%s/orgs/%s/teams/%s/memberships/%s", c.baseURL, org, TeamSlug(team), username)
+	log := c.log.With("action", "remove_team_member", "url", url, "username", username)
+	log.Info("Removing team member")
+
+	if err := c.do(log, http.MethodDelete, url, nil, http.StatusNoContent, nil); err != nil {
+		return fmt.Errorf("failed to remove %s from %s: %w", username, team, err)
+	}
+
+	log.Info("Successfully removed team member")
+	return nil
+}
+
+// do sends a request and decodes the response into out when it is not nil.
+// An optional accept header overrides the default media type.
+func (c *Client) do(log *slog.Logger, method string, url string, body any, want int, out any, accept ...string) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			log.Error("Failed to marshal request", "error", err)
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept[0])
+	}
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Error("Request failed", "error", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		log.Info("Not found")
+		return ErrNotFound
+	}
+	if resp.StatusCode != want {
+		var result struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		log.Error("Unexpected status code", "status", resp.StatusCode, "message", result.Message)
+		if result.Message != "" {
+			return fmt.Errorf("unexpected status code: %d (%s)", resp.StatusCode, result.Message)
+		}
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		log.Error("Failed to decode response", "error", err)
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// repoPattern matches the same links the API server looks for when it grants
+// the cohort team access to assignment repositories on publish.
+var repoPattern = regexp.MustCompile(`https?://github\.com/([^/\s"'<>]+)/([^/\s"'<>][a-zA-Z0-9._-]+)(?:\.git)?`)
+
+// reservedOwners are github.com paths that look like an owner but never hold
+// repositories.
+var reservedOwners = map[string]bool{
+	"orgs":          true,
+	"settings":      true,
+	"features":      true,
+	"topics":        true,
+	"marketplace":   true,
+	"notifications": true,
+	"login":         true,
+}
+
+type Repo struct {
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+}
+
+func (r Repo) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+func (r Repo) URL() string {
+	return fmt.Sprintf("https://github.com/%s/%s", r.Owner, r.Name)
+}
+
+// IsGitHubURL reports whether link points anywhere on github.com.
+func IsGitHubURL(link string) bool {
+	link = strings.ToLower(strings.TrimSpace(link))
+	return strings.HasPrefix(link, "https://github.com") || strings.HasPrefix(link, "http://github.com") ||
+		strings.HasPrefix(link, "https://www.github.com") || strings.HasPrefix(link, "http://www.github.com")
+}
+
+// ParseRepoURL returns the repository a github.com link points at.
+func ParseRepoURL(link string) (Repo, error) {
+	match := repoPattern.FindStringSubmatch(strings.TrimSpace(link))
+	if match == nil {
+		return Repo{}, fmt.Errorf("%s does not name a repository", link)
+	}
+
+	repo := Repo{Owner: match[1], Name: strings.TrimSuffix(match[2], ".git")}
+	if reservedOwners[strings.ToLower(repo.Owner)] {
+		return Repo{}, fmt.Errorf("%s is not a repository link", link)
+	}
+	return repo, nil
+}
+
+// FindRepos returns every repository linked from text, in order and without
+// duplicates.
+func FindRepos(text string) []Repo {
+	var repos []Repo
+	seen := map[string]bool{}
+	for _, match := range repoPattern.FindAllString(text, -1) {
+		repo, err := ParseRepoURL(match)
+		if err != nil {
+			continue
+		}
+		key := strings.ToLower(repo.String())
+		if !seen[key] {
+			seen[key] = true
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
@@ -0,0 +1,119 @@
+package gradebook
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+// Change is a score in the sheet that differs from Canvas.
+type Change struct {
+	UserID       int      `json:"user_id"`
+	Student      string   `json:"student"`
+	AssignmentID int      `json:"assignment_id"`
+	Assignment   string   `json:"assignment"`
+	Old          *float64 `json:"old"`
+	New          float64  `json:"new"`
+}
+
+type Diff struct {
+	Changes   []Change
+	Unchanged int
+	// Problems are rows and columns that don't match the course. Nothing
+	// should be posted until they're fixed.
+	Problems []string
+	// Warnings are rows that were skipped, like the test student's.
+	Warnings []string
+}
+
+// Compare checks the sheet against the course and lists the scores that
+// would change. Students must have a student enrollment and columns must be
+// assignments in the course; blank cells never clear a grade. Rows for the
+// test student and inactive students are skipped with a warning.
+func Compare(g *Gradebook, sheet Sheet) Diff {
+	var diff Diff
+
+	known := map[int]bool{}
+	for _, column := range sheet.Columns {
+		if known[column.AssignmentID] {
+			diff.Problems = append(diff.Problems, fmt.Sprintf("assignment %d (%s) has more than one column", column.AssignmentID, column.Name))
+			continue
+		}
+		if _, ok := g.Assignment(column.AssignmentID); !ok {
+			diff.Problems = append(diff.Problems, fmt.Sprintf("assignment %d (%s) is not in the course", column.AssignmentID, column.Name))
+			continue
+		}
+		known[column.AssignmentID] = true
+	}
+
+	seen := map[int]int{}
+	for _, row := range sheet.Rows {
+		if line, ok := seen[row.UserID]; ok {
+			diff.Problems = append(diff.Problems, fmt.Sprintf("line %d: student %d is already on line %d", row.Line, row.UserID, line))
+			continue
+		}
+		seen[row.UserID] = row.Line
+
+		if reason, ok := g.Skipped(row.UserID); ok {
+			diff.Warnings = append(diff.Warnings, fmt.Sprintf("line %d: skipped %s (%d), %s", row.Line, row.Student, row.UserID, reason))
+			continue
+		}
+		student, ok := g.Student(row.UserID)
+		if !ok && row.Student == TestStudentName {
+			diff.Warnings = append(diff.Warnings, fmt.Sprintf("line %d: skipped %s (%d), the test student", row.Line, row.Student, row.UserID))
+			continue
+		}
+		if !ok {
+			diff.Problems = append(diff.Problems, fmt.Sprintf("line %d: %s (%d) is not a student in the course", row.Line, row.Student, row.UserID))
+			continue
+		}
+
+		for assignmentID, score := range row.Scores {
+			if !known[assignmentID] {
+				continue
+			}
+			old := g.Score(row.UserID, assignmentID)
+			if old != nil && math.Abs(*old-score) < 1e-9 {
+				diff.Unchanged++
+				continue
+			}
+			assignment, _ := g.Assignment(assignmentID)
+			diff.Changes = append(diff.Changes, Change{
+				UserID:       row.UserID,
+				Student:      student.User.Name,
+				AssignmentID: assignmentID,
+				Assignment:   assignment.Name,
+				Old:          old,
+				New:          score,
+			})
+		}
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.Student != b.Student {
+			return a.Student < b.Student
+		}
+		return a.AssignmentID < b.AssignmentID
+	})
+	return diff
+}
+
+// Batches splits the changes into requests of at most size grades each.
+func Batches(changes []Change, size int) [][]api.GradeChange {
+	if size <= 0 {
+		size = len(changes)
+	}
+	var batches [][]api.GradeChange
+	for start := 0; start < len(changes); start += size {
+		end := min(start+size, len(changes))
+		var batch []api.GradeChange
+		for _, c := range changes[start:end] {
+			batch = append(batch, api.GradeChange{AssignmentID: c.AssignmentID, UserID: c.UserID, Score: c.New})
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
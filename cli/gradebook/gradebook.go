@@ -0,0 +1,123 @@
+// Package gradebook holds the course's scores as a students × assignments
+// grid and reads and diffs gradebook CSVs in the format Canvas exports.
+package gradebook
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+type cell struct {
+	userID       int
+	assignmentID int
+}
+
+// TestStudentName is how Canvas names the test student in gradebook exports.
+const TestStudentName = "Student, Test"
+
+type Gradebook struct {
+	Students    []api.Enrollment
+	Assignments []api.Assignment
+	submissions map[cell]api.Submission
+	// skipped says why users whose rows an import ignores are left out.
+	skipped map[int]string
+}
+
+// Build arranges submissions by student and assignment. Only student
+// enrollments become rows, one per student however many sections they are
+// in, sorted by name, and assignments keep Canvas' order. The test student
+// and students with no active enrollment are noted so imports can skip them.
+func Build(enrollments []api.Enrollment, assignments []api.Assignment, submissions []api.Submission) *Gradebook {
+	g := &Gradebook{
+		Assignments: assignments,
+		submissions: map[cell]api.Submission{},
+		skipped:     map[int]string{},
+	}
+	rows := map[int]int{}
+	for _, e := range enrollments {
+		switch {
+		case e.Type == "StudentViewEnrollment":
+			g.skipped[e.User.ID] = "the test student"
+			continue
+		case e.Type != "StudentEnrollment":
+			continue
+		}
+
+		i, ok := rows[e.User.ID]
+		if !ok {
+			rows[e.User.ID] = len(g.Students)
+			g.Students = append(g.Students, e)
+			if e.State == "inactive" {
+				g.skipped[e.User.ID] = "inactive"
+			}
+			continue
+		}
+		// An active enrollment in another section keeps the student active
+		if e.State != "inactive" && g.Students[i].State == "inactive" {
+			g.Students[i] = e
+			delete(g.skipped, e.User.ID)
+		}
+	}
+	sort.SliceStable(g.Students, func(i, j int) bool {
+		return strings.ToLower(g.Students[i].User.Name) < strings.ToLower(g.Students[j].User.Name)
+	})
+	for _, s := range submissions {
+		g.submissions[cell{s.UserID, s.AssignmentID}] = s
+	}
+	return g
+}
+
+// Load fetches the roster, assignments and every submission in the course.
+func Load(client *api.Client) (*Gradebook, error) {
+	enrollments, err := client.GetCourseEnrollments()
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := client.GetAssignments()
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := client.GetSubmissions()
+	if err != nil {
+		return nil, err
+	}
+	return Build(enrollments, assignments, submissions), nil
+}
+
+// Submission returns the student's submission for the assignment, if Canvas
+// has one.
+func (g *Gradebook) Submission(userID int, assignmentID int) (api.Submission, bool) {
+	s, ok := g.submissions[cell{userID, assignmentID}]
+	return s, ok
+}
+
+// Score is nil when the assignment hasn't been graded for the student.
+func (g *Gradebook) Score(userID int, assignmentID int) *float64 {
+	return g.submissions[cell{userID, assignmentID}].Score
+}
+
+func (g *Gradebook) Student(userID int) (api.Enrollment, bool) {
+	for _, e := range g.Students {
+		if e.User.ID == userID {
+			return e, true
+		}
+	}
+	return api.Enrollment{}, false
+}
+
+// Skipped returns why an import leaves the user's row alone, if it does.
+func (g *Gradebook) Skipped(userID int) (string, bool) {
+	reason, ok := g.skipped[userID]
+	return reason, ok
+}
+
+func (g *Gradebook) Assignment(assignmentID int) (api.Assignment, bool) {
+	for _, a := range g.Assignments {
+		if a.ID == assignmentID {
+			return a, true
+		}
+	}
+	return api.Assignment{}, false
+}
@@ -0,0 +1,208 @@
+package gradebook
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+func enrollment(id int, name string, typ string, state string) api.Enrollment {
+	return api.Enrollment{Type: typ, State: state, User: api.User{ID: id, Name: name}}
+}
+
+func score(v float64) *float64 {
+	return &v
+}
+
+func TestBuild(t *testing.T) {
+	g := Build([]api.Enrollment{
+		enrollment(2, "bob", "StudentEnrollment", "active"),
+		enrollment(1, "Ada", "StudentEnrollment", "active"),
+		enrollment(1, "Ada", "StudentEnrollment", "active"),
+		enrollment(3, "Cy", "StudentEnrollment", "inactive"),
+		enrollment(3, "Cy", "StudentEnrollment", "active"),
+		enrollment(4, "Di", "StudentEnrollment", "inactive"),
+		enrollment(5, "Test Student", "StudentViewEnrollment", "active"),
+		enrollment(6, "Tess", "TeacherEnrollment", "active"),
+	}, nil, nil)
+
+	var names []string
+	for _, s := range g.Students {
+		names = append(names, s.User.Name)
+	}
+	if want := []string{"Ada", "bob", "Cy", "Di"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Students = %v, want %v", names, want)
+	}
+
+	tests := []struct {
+		userID int
+		want   string
+		ok     bool
+	}{
+		{userID: 1},
+		{userID: 3},
+		{userID: 4, want: "inactive", ok: true},
+		{userID: 5, want: "the test student", ok: true},
+	}
+	for _, tc := range tests {
+		if got, ok := g.Skipped(tc.userID); got != tc.want || ok != tc.ok {
+			t.Errorf("Skipped(%d) = %q, %v, want %q, %v", tc.userID, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestReadSheet(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    Sheet
+		wantErr bool
+	}{
+		{
+			name: "canvas export",
+			csv: "\ufeffStudent,ID,SIS User ID,SIS Login ID,Section,Lab 1 (10),Quiz (Week 2) (11),Current Score\n" +
+				PointsPossibleLabel + ",,,,,10,5,\n" +
+				"\"Lovelace, Ada\",1,,,A,9.5,EX,90\n" +
+				"Bob,2,,,A,,4,80\n",
+			want: Sheet{
+				Columns: []Column{{AssignmentID: 10, Name: "Lab 1"}, {AssignmentID: 11, Name: "Quiz (Week 2)"}},
+				Rows: []Row{
+					{Line: 3, UserID: 1, Student: "Lovelace, Ada", Scores: map[int]float64{10: 9.5}},
+					{Line: 4, UserID: 2, Student: "Bob", Scores: map[int]float64{11: 4}},
+				},
+			},
+		},
+		{name: "no id column", csv: "Student,Lab 1 (10)\nAda,1\n", wantErr: true},
+		{name: "no assignment columns", csv: "Student,ID\nAda,1\n", wantErr: true},
+		{name: "negative score", csv: "ID,Lab 1 (10)\n1,-1\n", wantErr: true},
+		{name: "not a number", csv: "ID,Lab 1 (10)\n1,NaN\n", wantErr: true},
+		{name: "infinite", csv: "ID,Lab 1 (10)\n1,Inf\n", wantErr: true},
+		{name: "bad student id", csv: "ID,Lab 1 (10)\nabc,1\n", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReadSheet(strings.NewReader(tc.csv))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ReadSheet() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadSheet() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ReadSheet() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	g := Build(
+		[]api.Enrollment{
+			enrollment(1, "Ada", "StudentEnrollment", "active"),
+			enrollment(2, "Bob", "StudentEnrollment", "active"),
+			enrollment(3, "Cy", "StudentEnrollment", "inactive"),
+			enrollment(4, "Test Student", "StudentViewEnrollment", "active"),
+		},
+		[]api.Assignment{{ID: 10, Name: "Lab 1"}, {ID: 11, Name: "Lab 2"}},
+		[]api.Submission{
+			{UserID: 1, AssignmentID: 10, Score: score(8)},
+			{UserID: 2, AssignmentID: 10, Score: score(5)},
+		},
+	)
+
+	tests := []struct {
+		name  string
+		sheet Sheet
+		want  Diff
+	}{
+		{
+			name: "changed, new and unchanged scores",
+			sheet: Sheet{
+				Columns: []Column{{AssignmentID: 10, Name: "Lab 1"}, {AssignmentID: 11, Name: "Lab 2"}},
+				Rows: []Row{
+					{Line: 3, UserID: 2, Student: "Bob", Scores: map[int]float64{10: 5}},
+					{Line: 4, UserID: 1, Student: "Ada", Scores: map[int]float64{10: 9, 11: 7}},
+				},
+			},
+			want: Diff{
+				Changes: []Change{
+					{UserID: 1, Student: "Ada", AssignmentID: 10, Assignment: "Lab 1", Old: score(8), New: 9},
+					{UserID: 1, Student: "Ada", AssignmentID: 11, Assignment: "Lab 2", New: 7},
+				},
+				Unchanged: 1,
+			},
+		},
+		{
+			name: "test student and inactive students are skipped",
+			sheet: Sheet{
+				Columns: []Column{{AssignmentID: 10, Name: "Lab 1"}},
+				Rows: []Row{
+					{Line: 3, UserID: 3, Student: "Cy", Scores: map[int]float64{10: 1}},
+					{Line: 4, UserID: 4, Student: "Test Student", Scores: map[int]float64{10: 1}},
+					{Line: 5, UserID: 99, Student: TestStudentName, Scores: map[int]float64{10: 1}},
+				},
+			},
+			want: Diff{
+				Warnings: []string{
+					"line 3: skipped Cy (3), inactive",
+					"line 4: skipped Test Student (4), the test student",
+					"line 5: skipped Student, Test (99), the test student",
+				},
+			},
+		},
+		{
+			name: "unknown rows and columns are problems",
+			sheet: Sheet{
+				Columns: []Column{{AssignmentID: 10, Name: "Lab 1"}, {AssignmentID: 10, Name: "Lab 1"}, {AssignmentID: 12, Name: "Old"}},
+				Rows: []Row{
+					{Line: 3, UserID: 1, Student: "Ada", Scores: map[int]float64{12: 1}},
+					{Line: 4, UserID: 1, Student: "Ada", Scores: map[int]float64{}},
+					{Line: 5, UserID: 7, Student: "Eve", Scores: map[int]float64{}},
+				},
+			},
+			want: Diff{
+				Problems: []string{
+					"assignment 10 (Lab 1) has more than one column",
+					"assignment 12 (Old) is not in the course",
+					"line 4: student 1 is already on line 3",
+					"line 5: Eve (7) is not a student in the course",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Compare(g, tc.sheet); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Compare() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBatches(t *testing.T) {
+	changes := []Change{{UserID: 1, New: 1}, {UserID: 2, New: 2}, {UserID: 3, New: 3}}
+	tests := []struct {
+		size int
+		want []int
+	}{
+		{size: 2, want: []int{2, 1}},
+		{size: 3, want: []int{3}},
+		{size: 0, want: []int{3}},
+	}
+	for _, tc := range tests {
+		var sizes []int
+		for _, batch := range Batches(changes, tc.size) {
+			sizes = append(sizes, len(batch))
+		}
+		if !reflect.DeepEqual(sizes, tc.want) {
+			t.Errorf("Batches(size %d) sizes = %v, want %v", tc.size, sizes, tc.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package gradebook
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+// StudentColumns lead every row of a Canvas gradebook export, before one
+// column per assignment.
+var StudentColumns = []string{"Student", "ID", "SIS User ID", "SIS Login ID", "Section"}
+
+// PointsPossibleLabel marks the row under the header that holds each
+// assignment's points possible instead of a student's scores.
+const PointsPossibleLabel = "    Points Possible"
+
+// Excused is what Canvas writes in place of a score for excused work.
+const Excused = "EX"
+
+var assignmentColumn = regexp.MustCompile(`^(.*)\((\d+)\)\s*$`)
+
+// ColumnName is the header Canvas gives an assignment: its name followed by
+// its id in parentheses, which is how imports match columns to assignments.
+func ColumnName(a api.Assignment) string {
+	return fmt.Sprintf("%s (%d)", a.Name, a.ID)
+}
+
+type Column struct {
+	AssignmentID int
+	Name         string
+}
+
+// Row is one student's line of a gradebook CSV. Scores only holds the
+// cells that had a number; blank and excused cells are left out.
+type Row struct {
+	Line    int
+	UserID  int
+	Student string
+	Scores  map[int]float64
+}
+
+// Sheet is a gradebook CSV as read, before it's checked against the course.
+type Sheet struct {
+	Columns []Column
+	Rows    []Row
+}
+
+// ReadSheet reads a gradebook CSV exported from Canvas or from this tool.
+// Assignment columns are the ones whose header ends in "(id)"; anything
+// else, like totals Canvas appends, is ignored. Cells that aren't blank,
+// excused or a finite, non-negative number are an error.
+func ReadSheet(r io.Reader) (Sheet, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Sheet{}, fmt.Errorf("failed to read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return Sheet{}, fmt.Errorf("gradebook is empty")
+	}
+
+	idColumn := -1
+	studentColumn := -1
+	columns := map[int]Column{}
+	var sheet Sheet
+	for i, name := range records[0] {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		switch strings.ToLower(name) {
+		case "id":
+			idColumn = i
+			continue
+		case "student":
+			studentColumn = i
+			continue
+		}
+		match := assignmentColumn.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		id, _ := strconv.Atoi(match[2])
+		column := Column{AssignmentID: id, Name: strings.TrimSpace(match[1])}
+		columns[i] = column
+		sheet.Columns = append(sheet.Columns, column)
+	}
+	if idColumn < 0 {
+		return Sheet{}, fmt.Errorf("gradebook has no \"ID\" column")
+	}
+	if len(columns) == 0 {
+		return Sheet{}, fmt.Errorf("gradebook has no assignment columns")
+	}
+
+	for line, record := range records[1:] {
+		line += 2
+		cell := func(i int) string {
+			if i >= 0 && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		id := cell(idColumn)
+		if id == "" {
+			// The points possible and muted rows have no student id.
+			continue
+		}
+		row := Row{Line: line, Student: cell(studentColumn), Scores: map[int]float64{}}
+		if row.UserID, err = strconv.Atoi(id); err != nil {
+			return Sheet{}, fmt.Errorf("line %d: invalid student id %q", line, id)
+		}
+		for i, column := range columns {
+			value := cell(i)
+			if value == "" || strings.EqualFold(value, Excused) {
+				continue
+			}
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil || score < 0 || math.IsNaN(score) || math.IsInf(score, 0) {
+				return Sheet{}, fmt.Errorf("line %d: invalid score %q for %s", line, value, column.Name)
+			}
+			row.Scores[column.AssignmentID] = score
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet, nil
+}
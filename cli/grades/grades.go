@@ -0,0 +1,223 @@
+// Package grades works out course scores from assignment groups the way
+// Canvas does: group weights or total points, drop rules, and excused work
+// left out.
+package grades
+
+import (
+	"sort"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+// PassingScore is the course percentage treated as a pass. The roster lists
+// students below it as at risk, and the what-if calculator starts from it.
+const PassingScore = 70.0
+
+// Scores holds one student's points by assignment id. Assignments missing
+// from Points are ungraded.
+type Scores struct {
+	Points  map[int]float64
+	Excused map[int]bool
+}
+
+// StudentScores picks the student's graded and excused submissions.
+func StudentScores(submissions []api.Submission, userID int) Scores {
+	s := Scores{Points: map[int]float64{}, Excused: map[int]bool{}}
+	for _, submission := range submissions {
+		if submission.UserID != userID {
+			continue
+		}
+		switch {
+		case submission.Excused:
+			s.Excused[submission.AssignmentID] = true
+		case submission.Score != nil:
+			s.Points[submission.AssignmentID] = *submission.Score
+		}
+	}
+	return s
+}
+
+// With returns a copy of the scores with the hypothetical ones laid over
+// the real ones.
+func (s Scores) With(hypothetical map[int]float64) Scores {
+	out := Scores{Points: map[int]float64{}, Excused: s.Excused}
+	for id, points := range s.Points {
+		out.Points[id] = points
+	}
+	for id, points := range hypothetical {
+		out.Points[id] = points
+	}
+	return out
+}
+
+// Reweight returns a copy of the groups with the given weights, by group
+// id, in place of the current ones.
+func Reweight(groups api.AssignmentGroups, weights map[int]float64) api.AssignmentGroups {
+	out := api.AssignmentGroups{Weighted: groups.Weighted}
+	for _, group := range groups.Groups {
+		if weight, ok := weights[group.ID]; ok {
+			group.Weight = weight
+		}
+		out.Groups = append(out.Groups, group)
+	}
+	return out
+}
+
+// Counts reports whether the assignment is part of the course grade.
+func Counts(a api.Assignment) bool {
+	return a.Published && !a.OmitFromFinal
+}
+
+// Current is the percentage over graded work only, like Canvas' current
+// score. ok is false when nothing has been graded.
+func Current(groups api.AssignmentGroups, s Scores) (float64, bool) {
+	return total(groups, s, func(api.Assignment) (float64, bool) { return 0, false })
+}
+
+// Final counts ungraded work as zero, like Canvas' final score.
+func Final(groups api.AssignmentGroups, s Scores) float64 {
+	score, _ := total(groups, s, func(api.Assignment) (float64, bool) { return 0, true })
+	return score
+}
+
+// Remaining lists the counted assignments that have no score and aren't
+// excused, in group order.
+func Remaining(groups api.AssignmentGroups, s Scores) []api.Assignment {
+	var remaining []api.Assignment
+	for _, group := range groups.Groups {
+		for _, a := range group.Assignments {
+			if _, graded := s.Points[a.ID]; !graded && !s.Excused[a.ID] && Counts(a) {
+				remaining = append(remaining, a)
+			}
+		}
+	}
+	return remaining
+}
+
+// Needed is the share of points, from 0 to 1, the student needs on every
+// remaining assignment for the final score to reach target. ok is false
+// when full marks on everything left still fall short.
+func Needed(groups api.AssignmentGroups, s Scores, target float64) (float64, bool) {
+	at := func(share float64) float64 {
+		score, _ := total(groups, s, func(a api.Assignment) (float64, bool) {
+			return share * a.PointsPossible, true
+		})
+		return score
+	}
+
+	if at(0) >= target {
+		return 0, true
+	}
+	if at(1) < target {
+		return 1, false
+	}
+	low, high := 0.0, 1.0
+	for range 50 {
+		mid := (low + high) / 2
+		if at(mid) >= target {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return high, true
+}
+
+type item struct {
+	id       int
+	earned   float64
+	possible float64
+}
+
+// total works out the course percentage, asking fill for the points of
+// each ungraded assignment; assignments fill declines are left out.
+func total(groups api.AssignmentGroups, s Scores, fill func(api.Assignment) (float64, bool)) (float64, bool) {
+	var earned, possible, weighted, weights float64
+	for _, group := range groups.Groups {
+		var items []item
+		for _, a := range group.Assignments {
+			if !Counts(a) || s.Excused[a.ID] {
+				continue
+			}
+			points, graded := s.Points[a.ID]
+			if !graded {
+				if points, graded = fill(a); !graded {
+					continue
+				}
+			}
+			items = append(items, item{a.ID, points, a.PointsPossible})
+		}
+
+		groupEarned, groupPossible := sum(drop(items, group.Rules))
+		if groupPossible == 0 {
+			continue
+		}
+		earned += groupEarned
+		possible += groupPossible
+		weighted += group.Weight * groupEarned / groupPossible
+		weights += group.Weight
+	}
+
+	if groups.Weighted {
+		if weights == 0 {
+			return 0, false
+		}
+		return 100 * weighted / weights, true
+	}
+	if possible == 0 {
+		return 0, false
+	}
+	return 100 * earned / possible, true
+}
+
+// drop applies the group's drop lowest and drop highest rules, ranking
+// items by percentage. Assignments marked never drop stay, and at least one
+// item is always kept.
+func drop(items []item, rules api.AssignmentGroupRules) []item {
+	if rules.DropLowest == 0 && rules.DropHighest == 0 {
+		return items
+	}
+
+	never := map[int]bool{}
+	for _, id := range rules.NeverDrop {
+		never[id] = true
+	}
+	var kept, droppable []item
+	for _, it := range items {
+		if never[it.id] {
+			kept = append(kept, it)
+		} else {
+			droppable = append(droppable, it)
+		}
+	}
+	sort.SliceStable(droppable, func(i, j int) bool {
+		return percent(droppable[i]) < percent(droppable[j])
+	})
+
+	lowest := min(rules.DropLowest, len(droppable))
+	if len(kept) == 0 && lowest == len(droppable) {
+		lowest--
+	}
+	droppable = droppable[max(lowest, 0):]
+	highest := min(rules.DropHighest, len(droppable))
+	if len(kept) == 0 && highest == len(droppable) {
+		highest--
+	}
+	droppable = droppable[:len(droppable)-max(highest, 0)]
+	return append(kept, droppable...)
+}
+
+func percent(it item) float64 {
+	if it.possible == 0 {
+		return 1
+	}
+	return it.earned / it.possible
+}
+
+func sum(items []item) (earned float64, possible float64) {
+	for _, it := range items {
+		earned += it.earned
+		possible += it.possible
+	}
+	return earned, possible
+}
@@ -0,0 +1,178 @@
+package grades
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+func ids(items []item) []int {
+	var out []int
+	for _, it := range items {
+		out = append(out, it.id)
+	}
+	return out
+}
+
+func TestDrop(t *testing.T) {
+	// Percentages: 1 is 50%, 2 is 90%, 3 is 20%, 4 is 70%.
+	items := []item{{1, 5, 10}, {2, 9, 10}, {3, 2, 10}, {4, 7, 10}}
+
+	tests := []struct {
+		name  string
+		items []item
+		rules api.AssignmentGroupRules
+		want  []int
+	}{
+		{name: "no rules", items: items, want: []int{1, 2, 3, 4}},
+		{name: "drop lowest", items: items, rules: api.AssignmentGroupRules{DropLowest: 1}, want: []int{1, 4, 2}},
+		{name: "drop two lowest", items: items, rules: api.AssignmentGroupRules{DropLowest: 2}, want: []int{4, 2}},
+		{name: "drop highest", items: items, rules: api.AssignmentGroupRules{DropHighest: 1}, want: []int{3, 1, 4}},
+		{
+			name:  "never drop keeps the lowest",
+			items: items,
+			rules: api.AssignmentGroupRules{DropLowest: 1, NeverDrop: []int{3}},
+			want:  []int{3, 4, 2},
+		},
+		{
+			name:  "never drop on everything drops nothing",
+			items: items,
+			rules: api.AssignmentGroupRules{DropLowest: 2, NeverDrop: []int{1, 2, 3, 4}},
+			want:  []int{1, 2, 3, 4},
+		},
+		{
+			name:  "dropping everything keeps one",
+			items: items,
+			rules: api.AssignmentGroupRules{DropLowest: 10},
+			want:  []int{2},
+		},
+		{
+			name:  "dropping everything with never drop keeps only those",
+			items: items,
+			rules: api.AssignmentGroupRules{DropLowest: 10, NeverDrop: []int{1}},
+			want:  []int{1},
+		},
+		{
+			name:  "drop lowest and highest",
+			items: items,
+			rules: api.AssignmentGroupRules{DropLowest: 1, DropHighest: 1},
+			want:  []int{1, 4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ids(drop(tc.items, tc.rules)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("drop() kept %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func assignment(id int, points float64) api.Assignment {
+	return api.Assignment{ID: id, PointsPossible: points, Published: true}
+}
+
+func TestCurrentAndFinal(t *testing.T) {
+	labs := api.AssignmentGroup{ID: 1, Weight: 40, Assignments: []api.Assignment{assignment(1, 10), assignment(2, 10)}}
+	exams := api.AssignmentGroup{ID: 2, Weight: 60, Assignments: []api.Assignment{assignment(3, 100), assignment(4, 100)}}
+	unpublished := api.Assignment{ID: 5, PointsPossible: 50}
+	labs.Assignments = append(labs.Assignments, unpublished)
+
+	tests := []struct {
+		name      string
+		groups    api.AssignmentGroups
+		scores    Scores
+		current   float64
+		currentOK bool
+		final     float64
+	}{
+		{
+			name:      "total points",
+			groups:    api.AssignmentGroups{Groups: []api.AssignmentGroup{labs, exams}},
+			scores:    Scores{Points: map[int]float64{1: 10, 2: 5, 3: 60}},
+			current:   100 * 75.0 / 120,
+			currentOK: true,
+			final:     100 * 75.0 / 220,
+		},
+		{
+			name:      "weighted groups",
+			groups:    api.AssignmentGroups{Weighted: true, Groups: []api.AssignmentGroup{labs, exams}},
+			scores:    Scores{Points: map[int]float64{1: 10, 2: 5, 3: 60}},
+			current:   (40*0.75 + 60*0.6),
+			currentOK: true,
+			final:     (40*0.75 + 60*0.3),
+		},
+		{
+			name:      "excused work is left out",
+			groups:    api.AssignmentGroups{Groups: []api.AssignmentGroup{labs}},
+			scores:    Scores{Points: map[int]float64{1: 8}, Excused: map[int]bool{2: true}},
+			current:   80,
+			currentOK: true,
+			final:     80,
+		},
+		{
+			name:   "nothing graded",
+			groups: api.AssignmentGroups{Groups: []api.AssignmentGroup{labs, exams}},
+			scores: Scores{Points: map[int]float64{}},
+			final:  0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			current, ok := Current(tc.groups, tc.scores)
+			if ok != tc.currentOK || math.Abs(current-tc.current) > 1e-9 {
+				t.Errorf("Current() = %v, %v, want %v, %v", current, ok, tc.current, tc.currentOK)
+			}
+			if final := Final(tc.groups, tc.scores); math.Abs(final-tc.final) > 1e-9 {
+				t.Errorf("Final() = %v, want %v", final, tc.final)
+			}
+		})
+	}
+}
+
+func TestNeeded(t *testing.T) {
+	groups := api.AssignmentGroups{Groups: []api.AssignmentGroup{{
+		ID:          1,
+		Assignments: []api.Assignment{assignment(1, 50), assignment(2, 50)},
+	}}}
+
+	tests := []struct {
+		name   string
+		scores Scores
+		target float64
+		want   float64
+		ok     bool
+	}{
+		{name: "half needed", scores: Scores{Points: map[int]float64{1: 20}}, target: 45, want: 0.5, ok: true},
+		{name: "already passing", scores: Scores{Points: map[int]float64{1: 50}}, target: 40, want: 0, ok: true},
+		{name: "out of reach", scores: Scores{Points: map[int]float64{1: 0}}, target: 70, want: 1, ok: false},
+		{name: "full marks exactly", scores: Scores{Points: map[int]float64{1: 20}}, target: 70, want: 1, ok: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := Needed(groups, tc.scores, tc.target)
+			if ok != tc.ok || math.Abs(got-tc.want) > 1e-6 {
+				t.Errorf("Needed() = %v, %v, want %v, %v", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestNeededWithDrops(t *testing.T) {
+	// With the lowest of three quizzes dropped, a zero doesn't count against
+	// the student once two more are graded.
+	groups := api.AssignmentGroups{Groups: []api.AssignmentGroup{{
+		ID:          1,
+		Rules:       api.AssignmentGroupRules{DropLowest: 1},
+		Assignments: []api.Assignment{assignment(1, 10), assignment(2, 10), assignment(3, 10)},
+	}}}
+	got, ok := Needed(groups, Scores{Points: map[int]float64{1: 0}}, 80)
+	if !ok || math.Abs(got-0.8) > 1e-6 {
+		t.Errorf("Needed() = %v, %v, want 0.8, true", got, ok)
+	}
+}
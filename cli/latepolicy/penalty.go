@@ -0,0 +1,161 @@
+package latepolicy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+// CommentPrefix starts every penalty comment, so a penalty already applied
+// to the current attempt isn't applied twice.
+const CommentPrefix = "Late penalty:"
+
+type Penalty struct {
+	UserID       int       `json:"user_id"`
+	Student      string    `json:"student"`
+	AssignmentID int       `json:"assignment_id"`
+	Assignment   string    `json:"assignment"`
+	DueAt        time.Time `json:"due_at"`
+	Extended     bool      `json:"extended"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+	DaysLate     int       `json:"days_late"`
+	Percent      float64   `json:"percent"`
+	Points       float64   `json:"points_possible"`
+	Score        float64   `json:"score"`
+	Adjusted     float64   `json:"adjusted"`
+}
+
+// Deduction is the points taken off.
+func (p Penalty) Deduction() float64 {
+	return p.Score - p.Adjusted
+}
+
+// Comment explains the penalty to the student.
+func (p Penalty) Comment() string {
+	days := "1 day"
+	if p.DaysLate != 1 {
+		days = fmt.Sprintf("%d days", p.DaysLate)
+	}
+	due := "the due date"
+	if p.Extended {
+		due = "your extended due date"
+	}
+	return fmt.Sprintf("%s submitted %s after %s (%s), so %g%% of the %g points (%g) was deducted. Score %g → %g.",
+		CommentPrefix, days, due, p.DueAt.Local().Format("Jan 2 15:04"), p.Percent, p.Points, round(p.Deduction()), p.Score, p.Adjusted)
+}
+
+// Result is what the policy found for one assignment.
+type Result struct {
+	Penalties []Penalty
+	// Applied are late submissions whose current attempt already has a
+	// penalty comment.
+	Applied int
+	// Ungraded are late submissions without a score to deduct from yet.
+	Ungraded int
+}
+
+// DueAt is the student's due date: their own override's if they have one,
+// otherwise the assignment's. Section overrides aren't considered.
+func DueAt(assignment api.Assignment, userID int, overrides []api.AssignmentOverride) (*time.Time, bool) {
+	for _, o := range overrides {
+		if o.AssignmentID != assignment.ID || o.DueAt == nil {
+			continue
+		}
+		for _, id := range o.StudentIDs {
+			if id == userID {
+				return o.DueAt, true
+			}
+		}
+	}
+	return assignment.DueAt, false
+}
+
+// HasDueDate reports whether anyone has a due date for the assignment,
+// whether from the assignment itself or a student's override.
+func HasDueDate(assignment api.Assignment, overrides []api.AssignmentOverride) bool {
+	if assignment.DueAt != nil {
+		return true
+	}
+	for _, o := range overrides {
+		if o.AssignmentID == assignment.ID && o.DueAt != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Compute checks each submission against the student's due date and works
+// out the penalty for the late ones.
+func (p Policy) Compute(assignment api.Assignment, submissions []api.Submission, overrides []api.AssignmentOverride) Result {
+	var result Result
+	for _, s := range submissions {
+		if s.SubmittedAt == nil || s.Excused {
+			continue
+		}
+		dueAt, extended := DueAt(assignment, s.UserID, overrides)
+		if dueAt == nil {
+			continue
+		}
+		late := s.SubmittedAt.Sub(*dueAt)
+		if late <= p.Grace || late <= 0 {
+			continue
+		}
+		if s.Score == nil {
+			result.Ungraded++
+			continue
+		}
+		if penalized(s) {
+			result.Applied++
+			continue
+		}
+
+		days := int(math.Ceil(late.Hours() / 24))
+		percent := math.Min(float64(days)*p.PerDay, p.Cap)
+		if percent <= 0 {
+			continue
+		}
+		adjusted := math.Max(round(*s.Score-percent/100*assignment.PointsPossible), 0)
+		if adjusted == *s.Score {
+			continue
+		}
+		result.Penalties = append(result.Penalties, Penalty{
+			UserID:       s.UserID,
+			Student:      s.User.Name,
+			AssignmentID: assignment.ID,
+			Assignment:   assignment.Name,
+			DueAt:        *dueAt,
+			Extended:     extended,
+			SubmittedAt:  *s.SubmittedAt,
+			DaysLate:     days,
+			Percent:      percent,
+			Points:       assignment.PointsPossible,
+			Score:        *s.Score,
+			Adjusted:     adjusted,
+		})
+	}
+
+	sort.Slice(result.Penalties, func(i, j int) bool {
+		return strings.ToLower(result.Penalties[i].Student) < strings.ToLower(result.Penalties[j].Student)
+	})
+	return result
+}
+
+// penalized reports whether a penalty comment was left after the latest
+// attempt was submitted.
+func penalized(s api.Submission) bool {
+	for _, c := range s.Comments {
+		if strings.HasPrefix(c.Comment, CommentPrefix) && !c.CreatedAt.Before(*s.SubmittedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// round keeps scores to two decimal places.
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,114 @@
+package latepolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+var due = time.Date(2026, 3, 2, 23, 59, 0, 0, time.UTC)
+
+func submission(userID int, late time.Duration, score float64) api.Submission {
+	submittedAt := due.Add(late)
+	return api.Submission{UserID: userID, SubmittedAt: &submittedAt, Score: &score, User: api.User{ID: userID}}
+}
+
+func TestCompute(t *testing.T) {
+	assignment := api.Assignment{ID: 1, Name: "Lab", PointsPossible: 20, DueAt: &due}
+	extendedDue := due.Add(48 * time.Hour)
+	overrides := []api.AssignmentOverride{{AssignmentID: 1, StudentIDs: []int{7}, DueAt: &extendedDue}}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		sub      api.Submission
+		days     int
+		percent  float64
+		adjusted float64
+		extended bool
+	}{
+		{name: "one day", policy: DefaultPolicy, sub: submission(1, time.Hour, 18), days: 1, percent: 10, adjusted: 16},
+		{name: "part days round up", policy: DefaultPolicy, sub: submission(1, 25*time.Hour, 18), days: 2, percent: 20, adjusted: 14},
+		{name: "capped", policy: DefaultPolicy, sub: submission(1, 10*24*time.Hour, 18), days: 10, percent: 50, adjusted: 8},
+		{name: "never below zero", policy: DefaultPolicy, sub: submission(1, 3*24*time.Hour, 2), days: 3, percent: 30, adjusted: 0},
+		{name: "on time", policy: DefaultPolicy, sub: submission(1, 0, 18)},
+		{name: "within grace", policy: Policy{PerDay: 10, Cap: 50, Grace: time.Hour}, sub: submission(1, time.Hour, 18)},
+		{
+			name:   "grace doesn't move the days",
+			policy: Policy{PerDay: 10, Cap: 50, Grace: time.Hour},
+			sub:    submission(1, 2*time.Hour, 18), days: 1, percent: 10, adjusted: 16,
+		},
+		{name: "extension not yet passed", policy: DefaultPolicy, sub: submission(7, 24*time.Hour, 18)},
+		{
+			name:   "late against the extension",
+			policy: DefaultPolicy,
+			sub:    submission(7, 49*time.Hour, 18), days: 1, percent: 10, adjusted: 16, extended: true,
+		},
+		{name: "zero percent a day", policy: Policy{PerDay: 0, Cap: 50}, sub: submission(1, 24*time.Hour, 18)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.policy.Compute(assignment, []api.Submission{tc.sub}, overrides)
+			if tc.days == 0 {
+				if len(result.Penalties) != 0 {
+					t.Fatalf("Compute() = %+v, want no penalty", result.Penalties)
+				}
+				return
+			}
+			if len(result.Penalties) != 1 {
+				t.Fatalf("Compute() returned %d penalties, want 1", len(result.Penalties))
+			}
+			p := result.Penalties[0]
+			if p.DaysLate != tc.days || p.Percent != tc.percent || p.Adjusted != tc.adjusted || p.Extended != tc.extended {
+				t.Errorf("Compute() = %d days, %g%%, %g, extended %v, want %d days, %g%%, %g, extended %v",
+					p.DaysLate, p.Percent, p.Adjusted, p.Extended, tc.days, tc.percent, tc.adjusted, tc.extended)
+			}
+		})
+	}
+}
+
+func TestComputeCounts(t *testing.T) {
+	assignment := api.Assignment{ID: 1, PointsPossible: 20, DueAt: &due}
+	ungraded := submission(1, 24*time.Hour, 0)
+	ungraded.Score = nil
+	excused := submission(2, 24*time.Hour, 18)
+	excused.Excused = true
+	penalized := submission(3, 24*time.Hour, 16)
+	penalized.Comments = []api.SubmissionComment{{Comment: CommentPrefix + " submitted 1 day after", CreatedAt: due.Add(48 * time.Hour)}}
+	resubmitted := submission(4, 72*time.Hour, 18)
+	resubmitted.Comments = []api.SubmissionComment{{Comment: CommentPrefix + " submitted 1 day after", CreatedAt: due.Add(48 * time.Hour)}}
+
+	result := DefaultPolicy.Compute(assignment, []api.Submission{ungraded, excused, penalized, resubmitted}, nil)
+	if result.Ungraded != 1 || result.Applied != 1 || len(result.Penalties) != 1 || result.Penalties[0].UserID != 4 {
+		t.Errorf("Compute() = %d ungraded, %d applied, %+v, want 1 ungraded, 1 applied and a penalty for user 4",
+			result.Ungraded, result.Applied, result.Penalties)
+	}
+}
+
+func TestHasDueDate(t *testing.T) {
+	overrides := []api.AssignmentOverride{
+		{AssignmentID: 2, StudentIDs: []int{1}, DueAt: &due},
+		{AssignmentID: 3, StudentIDs: []int{1}},
+	}
+
+	tests := []struct {
+		name       string
+		assignment api.Assignment
+		want       bool
+	}{
+		{name: "course due date", assignment: api.Assignment{ID: 1, DueAt: &due}, want: true},
+		{name: "override due date", assignment: api.Assignment{ID: 2}, want: true},
+		{name: "override without a due date", assignment: api.Assignment{ID: 3}, want: false},
+		{name: "no due date", assignment: api.Assignment{ID: 4}, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := HasDueDate(tc.assignment, overrides); got != tc.want {
+				t.Errorf("HasDueDate(%d) = %v, want %v", tc.assignment.ID, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Package latepolicy works out late penalties from submission times, due
+// dates and student overrides, for instructors to review and apply.
+package latepolicy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy deducts PerDay percent of the points possible for each day, or
+// part of a day, past the due date, up to Cap percent. Work submitted
+// within Grace of the due date isn't late.
+type Policy struct {
+	PerDay float64       `yaml:"per_day"`
+	Cap    float64       `yaml:"cap"`
+	Grace  time.Duration `yaml:"grace,omitempty"`
+}
+
+// DefaultPolicy is the program's standard 10% a day, at most 50%.
+var DefaultPolicy = Policy{PerDay: 10, Cap: 50}
+
+// DefaultPath is where the policy lives unless LATE_POLICY is set.
+func DefaultPath() string {
+	if path := os.Getenv("LATE_POLICY"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "canvasInstructor", "late-policy.yaml")
+}
+
+// Load reads the policy at path. A missing file is the default policy.
+func Load(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultPolicy, nil
+	}
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to read late policy: %w", err)
+	}
+
+	policy := DefaultPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return Policy{}, fmt.Errorf("failed to parse late policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return Policy{}, fmt.Errorf("late policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+func (p Policy) Validate() error {
+	if p.PerDay < 0 || p.PerDay > 100 {
+		return fmt.Errorf("per_day must be between 0 and 100, got %g", p.PerDay)
+	}
+	if p.Cap < 0 || p.Cap > 100 {
+		return fmt.Errorf("cap must be between 0 and 100, got %g", p.Cap)
+	}
+	if p.Grace < 0 {
+		return fmt.Errorf("grace can't be negative")
+	}
+	return nil
+}
+
+func (p Policy) String() string {
+	s := fmt.Sprintf("%g%% per day late, at most %g%%", p.PerDay, p.Cap)
+	if p.Grace > 0 {
+		s += fmt.Sprintf(", %s grace period", p.Grace)
+	}
+	return s
+}
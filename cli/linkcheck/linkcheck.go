@@ -0,0 +1,222 @@
+// Package linkcheck finds the links in a module and checks that they still
+// resolve.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/content"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+)
+
+const (
+	DefaultConcurrency = 8
+	DefaultTimeout     = 10 * time.Second
+)
+
+type Status string
+
+const (
+	StatusOK         Status = "ok"
+	StatusRedirected Status = "redirected"
+	StatusPrivate    Status = "private"
+	StatusDead       Status = "dead"
+	// StatusPrivateOrMissing is a GitHub repository that answered 404 when
+	// there was no token to tell a private repository from a deleted one.
+	StatusPrivateOrMissing Status = "private or missing"
+)
+
+// Link is a URL and the module items it appears in.
+type Link struct {
+	URL     string   `json:"url"`
+	Sources []string `json:"sources"`
+}
+
+type Result struct {
+	Link
+	Status     Status `json:"status"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Location   string `json:"location,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Collect returns every absolute http(s) link in the module: ExternalUrl items
+// plus links in assignment descriptions and page bodies. Links are returned in
+// the order they are first seen.
+func Collect(src content.Source, nodes []api.ModuleNode) ([]Link, error) {
+	var links []Link
+	index := map[string]int{}
+	add := func(link string, source string) {
+		link = strings.TrimSpace(link)
+		parsed, err := neturl.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return
+		}
+		i, ok := index[link]
+		if !ok {
+			index[link] = len(links)
+			links = append(links, Link{URL: link, Sources: []string{source}})
+			return
+		}
+		for _, s := range links[i].Sources {
+			if s == source {
+				return
+			}
+		}
+		links[i].Sources = append(links[i].Sources, source)
+	}
+
+	err := content.Walk(src, nodes, func(s content.Set, item api.Lesson) {
+		for _, link := range s.Links(item) {
+			add(link, item.Title)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+type Options struct {
+	// Concurrency bounds the number of requests in flight.
+	Concurrency int
+	// Timeout applies to each request.
+	Timeout time.Duration
+	// Client is used for requests. It must not follow redirects; the default
+	// client is set up that way.
+	Client *http.Client
+	// GitHub looks up repository links that answer 404. Without it those
+	// links are reported as private or missing.
+	GitHub *github.Client
+}
+
+// Check requests every link, at most opts.Concurrency at a time, and returns
+// the results in the same order as links.
+func Check(ctx context.Context, links []Link, opts Options) []Result {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+
+	results := make([]Result, len(links))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, link := range links {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = checkLink(ctx, opts, link)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func checkLink(ctx context.Context, opts Options, link Link) Result {
+	result := Result{Link: link}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.URL, nil)
+	if err != nil {
+		result.Status = StatusDead
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", "canvasInstructor-linkcheck")
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		result.Status = StatusDead
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = fmt.Sprintf("timed out after %s", opts.Timeout)
+		} else {
+			result.Error = err.Error()
+		}
+		return result
+	}
+	resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		result.Status = StatusRedirected
+		if location, err := resp.Location(); err == nil {
+			result.Location = location.String()
+		}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		result.Status = StatusPrivate
+	case resp.StatusCode == http.StatusNotFound && isGitHubRepo(link.URL):
+		// GitHub answers 404 rather than 403 for private repositories, so
+		// an anonymous check can't tell them apart from deleted ones.
+		result.Status, result.Error = checkGitHubRepo(opts.GitHub, link.URL)
+	case resp.StatusCode >= 400:
+		result.Status = StatusDead
+	default:
+		result.Status = StatusOK
+	}
+	return result
+}
+
+// checkGitHubRepo asks the API about a repository page that answered 404.
+// A repository the token can't see either is reported as dead.
+func checkGitHubRepo(gh *github.Client, link string) (Status, string) {
+	if gh == nil {
+		return StatusPrivateOrMissing, "repository is private or does not exist, set GITHUB_TOKEN to check"
+	}
+	repo, err := github.ParseRepoURL(link)
+	if err != nil {
+		return StatusDead, err.Error()
+	}
+	if _, err := gh.GetRepository(repo); err != nil {
+		if errors.Is(err, github.ErrNotFound) {
+			return StatusDead, "repository does not exist or GITHUB_TOKEN can't see it"
+		}
+		return StatusPrivateOrMissing, err.Error()
+	}
+	return StatusPrivate, "repository is private"
+}
+
+func isGitHubRepo(link string) bool {
+	if !github.IsGitHubURL(link) {
+		return false
+	}
+	_, err := github.ParseRepoURL(link)
+	return err == nil
+}
+
+// Problems returns the results that are not ok, dead links first.
+func Problems(results []Result) []Result {
+	rank := map[Status]int{StatusDead: 0, StatusPrivateOrMissing: 1, StatusPrivate: 2, StatusRedirected: 3}
+	var problems []Result
+	for _, r := range results {
+		if r.Status != StatusOK {
+			problems = append(problems, r)
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return rank[problems[i].Status] < rank[problems[j].Status]
+	})
+	return problems
+}
@@ -0,0 +1,167 @@
+// Package lint checks lessons for problems that students would hit once the
+// lesson is published.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/content"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/markup"
+)
+
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	ModuleID int      `json:"module_id"`
+	Module   string   `json:"module"`
+	Lesson   string   `json:"lesson"`
+	ItemID   int      `json:"item_id"`
+	Item     string   `json:"item"`
+	Message  string   `json:"message"`
+}
+
+// Source is the part of the API client the rules need.
+type Source interface {
+	GetAssignment(assignmentID int) (api.Assignment, error)
+	GetPage(pageURL string) (api.Page, error)
+}
+
+// Target is one lesson with everything the rules look at: its place in the
+// module, the details of its assignments and pages, and the pages they link
+// to.
+type Target struct {
+	Module api.Module
+	Nodes  []api.ModuleNode
+	Index  int
+
+	content.Set
+	// LinkedPages holds the course pages linked from the lesson's bodies. A
+	// nil entry means the page could not be found.
+	LinkedPages map[string]*api.Page
+}
+
+// Lesson returns the node being linted.
+func (t *Target) Lesson() api.ModuleNode {
+	return t.Nodes[t.Index]
+}
+
+// Items returns the lesson item followed by its children.
+func (t *Target) Items() []api.Lesson {
+	lesson := t.Lesson()
+	return append([]api.Lesson{lesson.Lesson}, lesson.Children...)
+}
+
+// Issue is a problem a rule found with a single item.
+type Issue struct {
+	Item    api.Lesson
+	Message string
+}
+
+type Rule struct {
+	Name        string
+	Severity    Severity
+	Description string
+	Check       func(t *Target) []Issue
+}
+
+var pageLinkPattern = regexp.MustCompile(`/courses/\d+/pages/([^/?#"]+)`)
+
+// LintLesson runs every rule over the lesson at index in nodes.
+func LintLesson(src Source, module api.Module, nodes []api.ModuleNode, index int) ([]Finding, error) {
+	target, err := load(src, module, nodes, index)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	lesson := target.Lesson().Lesson.Title
+	for _, rule := range Rules {
+		for _, issue := range rule.Check(target) {
+			findings = append(findings, Finding{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				ModuleID: module.ID,
+				Module:   module.Name,
+				Lesson:   lesson,
+				ItemID:   issue.Item.ID,
+				Item:     issue.Item.Title,
+				Message:  issue.Message,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// LintModule runs every rule over each lesson in the module.
+func LintModule(src Source, module api.Module, nodes []api.ModuleNode) ([]Finding, error) {
+	var findings []Finding
+	for i := range nodes {
+		lessonFindings, err := LintLesson(src, module, nodes, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", nodes[i].Lesson.Title, err)
+		}
+		findings = append(findings, lessonFindings...)
+	}
+	return findings, nil
+}
+
+// Count returns the number of errors and warnings in findings.
+func Count(findings []Finding) (errors int, warnings int) {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+func load(src Source, module api.Module, nodes []api.ModuleNode, index int) (*Target, error) {
+	t := &Target{
+		Module:      module,
+		Nodes:       nodes,
+		Index:       index,
+		LinkedPages: map[string]*api.Page{},
+	}
+
+	set, err := content.Fetch(src, t.Items())
+	if err != nil {
+		return nil, err
+	}
+	t.Set = set
+
+	for _, item := range t.Items() {
+		for _, link := range markup.Links(t.Body(item)) {
+			match := pageLinkPattern.FindStringSubmatch(link)
+			if match == nil {
+				continue
+			}
+			slug := match[1]
+			if _, ok := t.LinkedPages[slug]; ok {
+				continue
+			}
+			if page, ok := t.Pages[slug]; ok {
+				t.LinkedPages[slug] = &page
+				continue
+			}
+			page, err := src.GetPage(slug)
+			if err != nil {
+				t.LinkedPages[slug] = nil
+				continue
+			}
+			t.LinkedPages[slug] = &page
+		}
+	}
+
+	return t, nil
+}
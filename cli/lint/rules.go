@@ -0,0 +1,150 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/markup"
+)
+
+// Rules are run in this order, so findings for a lesson come out grouped by
+// rule.
+var Rules = []Rule{
+	{
+		Name:        "no-due-date",
+		Severity:    SeverityWarning,
+		Description: "assignments should have a due date",
+		Check:       checkDueDate,
+	},
+	{
+		Name:        "zero-points",
+		Severity:    SeverityWarning,
+		Description: "assignments should be worth points",
+		Check:       checkPoints,
+	},
+	{
+		Name:        "broken-github-link",
+		Severity:    SeverityError,
+		Description: "GitHub links must name a repository",
+		Check:       checkGitHubLinks,
+	},
+	{
+		Name:        "extra-github-repo",
+		Severity:    SeverityWarning,
+		Description: "publishing only grants the cohort access to the first repository in an assignment",
+		Check:       checkExtraRepos,
+	},
+	{
+		Name:        "unpublished-prerequisite-page",
+		Severity:    SeverityError,
+		Description: "pages linked from the lesson or earlier in the module must be published",
+		Check:       checkPrerequisitePages,
+	},
+}
+
+func checkDueDate(t *Target) []Issue {
+	var issues []Issue
+	for _, item := range t.Items() {
+		if item.Type != "Assignment" {
+			continue
+		}
+		if assignment, ok := t.Assignments[item.ContentID]; ok && assignment.DueAt == nil {
+			issues = append(issues, Issue{Item: item, Message: "assignment has no due date"})
+		}
+	}
+	return issues
+}
+
+func checkPoints(t *Target) []Issue {
+	var issues []Issue
+	for _, item := range t.Items() {
+		if item.Type != "Assignment" {
+			continue
+		}
+		if assignment, ok := t.Assignments[item.ContentID]; ok && assignment.PointsPossible == 0 {
+			issues = append(issues, Issue{Item: item, Message: "assignment is worth 0 points"})
+		}
+	}
+	return issues
+}
+
+func checkGitHubLinks(t *Target) []Issue {
+	var issues []Issue
+	for _, item := range t.Items() {
+		for _, link := range t.Links(item) {
+			if !github.IsGitHubURL(link) {
+				continue
+			}
+			if _, err := github.ParseRepoURL(link); err != nil {
+				issues = append(issues, Issue{Item: item, Message: err.Error()})
+			}
+		}
+	}
+	return issues
+}
+
+func checkExtraRepos(t *Target) []Issue {
+	var issues []Issue
+	for _, item := range t.Items() {
+		if item.Type != "Assignment" {
+			continue
+		}
+		repos := github.FindRepos(t.Body(item))
+		if len(repos) < 2 {
+			continue
+		}
+
+		var extra []string
+		for _, repo := range repos[1:] {
+			extra = append(extra, repo.String())
+		}
+		issues = append(issues, Issue{
+			Item:    item,
+			Message: fmt.Sprintf("only %s is granted on publish, not %s", repos[0], strings.Join(extra, ", ")),
+		})
+	}
+	return issues
+}
+
+func checkPrerequisitePages(t *Target) []Issue {
+	var issues []Issue
+	lesson := t.Lesson()
+
+	// Pages in earlier lessons of the module that students reach first.
+	for _, node := range t.Nodes[:t.Index] {
+		for _, item := range append([]api.Lesson{node.Lesson}, node.Children...) {
+			if item.Type == "Page" && !item.Published {
+				issues = append(issues, Issue{
+					Item:    lesson.Lesson,
+					Message: fmt.Sprintf("earlier page %q in %s is unpublished", item.Title, node.Lesson.Title),
+				})
+			}
+		}
+	}
+
+	// Pages linked from the lesson's own bodies that publishing won't touch.
+	own := map[string]bool{}
+	for _, item := range t.Items() {
+		if item.Type == "Page" {
+			own[item.PageURL] = true
+		}
+	}
+	for _, item := range t.Items() {
+		for _, link := range markup.Links(t.Body(item)) {
+			match := pageLinkPattern.FindStringSubmatch(link)
+			if match == nil || own[match[1]] {
+				continue
+			}
+			page := t.LinkedPages[match[1]]
+			switch {
+			case page == nil:
+				issues = append(issues, Issue{Item: item, Message: fmt.Sprintf("links to missing page %q", match[1])})
+			case !page.Published:
+				issues = append(issues, Issue{Item: item, Message: fmt.Sprintf("links to unpublished page %q", page.Title)})
+			}
+		}
+	}
+	return issues
+}
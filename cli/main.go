@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,6 +30,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if c, ok := m.currentView.(views.InputCapturer); ok && c.CapturingInput() && msg.String() == "q" {
+			break
+		}
 		if msg.String() == "ctrl+c" || msg.String() == "q" {
 			log.Info("User requested exit")
 			return m, tea.Quit
@@ -45,20 +49,80 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		log.Info("Switching to enrollments view")
 		m.currentView = views.NewEnrollmentView()
 		return m, m.currentView.Init()
+	case views.ToGradeView:
+		log.Info("Switching to to grade view")
+		m.currentView = views.NewToGradeView()
+		return m, m.currentView.Init()
+	case views.AnnouncementView:
+		log.Info("Switching to announcement view")
+		m.currentView = views.NewAnnouncementView()
+		return m, m.currentView.Init()
+	case views.FeedbackLibraryView:
+		log.Info("Switching to feedback library view")
+		m.currentView = views.NewFeedbackLibraryView()
+		return m, m.currentView.Init()
+	case views.OverridesView:
+		log.Info("Switching to overrides view")
+		m.currentView = views.NewOverridesView()
+		return m, m.currentView.Init()
+	case views.GradingPolicyView:
+		log.Info("Switching to grading policy view")
+		m.currentView = views.NewGradingPolicyView()
+		return m, m.currentView.Init()
+	case views.GitHubAccessView:
+		log.Info("Switching to GitHub access view")
+		m.currentView = views.NewGitHubAccessView()
+		return m, m.currentView.Init()
+	case views.TeamSyncView:
+		log.Info("Switching to team sync view")
+		m.currentView = views.NewTeamSyncView()
+		return m, m.currentView.Init()
+	case views.SubmissionSelectedMsg:
+		log.Info("Switching to grade view", "submission_id", msg.Submission.ID)
+		m.currentView = views.NewGradeView(msg.Submission)
+		return m, m.currentView.Init()
 	case views.ModuleSelectedMsg:
 		log.Info("Switching to module view", "module_name", msg.Module.Name)
 		m.currentView = views.NewModuleView(msg.Module)
 		return m, m.currentView.Init()
+	case views.HeatmapSelectedMsg:
+		log.Info("Switching to heatmap view", "module_name", msg.Module.Name)
+		m.currentView = views.NewHeatmapView(msg.Module)
+		return m, m.currentView.Init()
+	case views.LessonTemplateMsg:
+		log.Info("Switching to lesson template view", "module_name", msg.Module.Name)
+		m.currentView = views.NewLessonTemplateView(msg.Module, msg.Lessons)
+		return m, m.currentView.Init()
+	case views.LinkCheckSelectedMsg:
+		log.Info("Switching to link check view", "module_name", msg.Module.Name)
+		m.currentView = views.NewLinkCheckView(msg.Module)
+		return m, m.currentView.Init()
 	case views.LessonSelectedMsg:
 		log.Info("Switching to lesson view",
 			"lesson_id", msg.Lesson.Lesson.ID,
 			"lesson_title", msg.Lesson.Lesson.Title)
 		m.currentView = views.NewLessonView(msg.Lesson, msg.Module)
 		return m, m.currentView.Init()
+	case views.EditContentMsg:
+		log.Info("Switching to content edit view", "lesson_id", msg.Lesson.Lesson.ID)
+		m.currentView = views.NewContentEditView(msg.Lesson, msg.Module)
+		return m, m.currentView.Init()
 	case views.EnrollmentSelectedMsg:
-		log.Info("Enrollment selected", "student_name", msg.Enrollment.User.Name)
-		// Handle enrollment selection - maybe show detailed view?
-		// For now, staying on the same view
+		log.Info("Switching to student view", "student_name", msg.Enrollment.User.Name)
+		m.currentView = views.NewStudentView(msg.Enrollment)
+		return m, m.currentView.Init()
+	case views.WhatIfSelectedMsg:
+		log.Info("Switching to what-if view", "student_name", msg.Enrollment.User.Name)
+		m.currentView = views.NewWhatIfView(msg.Enrollment)
+		return m, m.currentView.Init()
+	case views.ExtendDeadlineMsg:
+		log.Info("Switching to extend deadline view", "student_name", msg.Enrollment.User.Name)
+		m.currentView = views.NewExtendDeadlineView(msg.Enrollment)
+		return m, m.currentView.Init()
+	case views.ComposeMessageMsg:
+		log.Info("Switching to message view", "recipients", len(msg.Recipients))
+		m.currentView = views.NewMessageView(msg.Recipients, msg.Back)
+		return m, m.currentView.Init()
 	}
 
 	var cmd tea.Cmd
@@ -74,6 +138,15 @@ func main() {
 	log := logger.With("component", "main")
 	log.Info("Starting Canvas Instructor CLI")
 
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			logger.Logger.Error("Command error", "error", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	p := tea.NewProgram(initialModel())
 	if _, err := p.Run(); err != nil {
 		logger.Logger.Error("Application error", "error", err)
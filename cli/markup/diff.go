@@ -0,0 +1,73 @@
+package markup
+
+import "strings"
+
+type DiffOp byte
+
+const (
+	DiffEqual  DiffOp = ' '
+	DiffInsert DiffOp = '+'
+	DiffDelete DiffOp = '-'
+)
+
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// Diff returns a line diff turning a into b, using the longest common
+// subsequence of lines. Content edits are small enough that the quadratic
+// table is not a concern.
+func Diff(a, b string) []DiffLine {
+	from := strings.Split(strings.TrimRight(a, "\n"), "\n")
+	to := strings.Split(strings.TrimRight(b, "\n"), "\n")
+
+	// lcs[i][j] is the LCS length of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			lines = append(lines, DiffLine{DiffEqual, from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{DiffDelete, from[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{DiffInsert, to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		lines = append(lines, DiffLine{DiffDelete, from[i]})
+	}
+	for ; j < len(to); j++ {
+		lines = append(lines, DiffLine{DiffInsert, to[j]})
+	}
+	return lines
+}
+
+// Changed reports whether the diff contains any insertions or deletions.
+func Changed(lines []DiffLine) bool {
+	for _, line := range lines {
+		if line.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
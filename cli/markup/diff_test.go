@@ -0,0 +1,71 @@
+package markup
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []DiffLine
+	}{
+		{
+			name: "unchanged",
+			a:    "one\ntwo\n",
+			b:    "one\ntwo",
+			want: []DiffLine{{DiffEqual, "one"}, {DiffEqual, "two"}},
+		},
+		{
+			name: "line changed",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []DiffLine{{DiffEqual, "one"}, {DiffDelete, "two"}, {DiffInsert, "2"}, {DiffEqual, "three"}},
+		},
+		{
+			name: "appended",
+			a:    "one",
+			b:    "one\ntwo",
+			want: []DiffLine{{DiffEqual, "one"}, {DiffInsert, "two"}},
+		},
+		{
+			name: "removed from the start",
+			a:    "zero\none\ntwo",
+			b:    "one\ntwo",
+			want: []DiffLine{{DiffDelete, "zero"}, {DiffEqual, "one"}, {DiffEqual, "two"}},
+		},
+		{
+			name: "moved line",
+			a:    "a\nb\nc",
+			b:    "b\nc\na",
+			want: []DiffLine{{DiffDelete, "a"}, {DiffEqual, "b"}, {DiffEqual, "c"}, {DiffInsert, "a"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Diff(tc.a, tc.b)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Diff(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"same\n", "same", false},
+		{"", "", false},
+		{"one", "two", true},
+		{"one", "one\n\ntwo", true},
+	}
+	for _, tc := range tests {
+		if got := Changed(Diff(tc.a, tc.b)); got != tc.want {
+			t.Errorf("Changed(Diff(%q, %q)) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package markup
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Raw HTML in the Markdown is dropped rather than passed through, so what we
+// send to Canvas is only what Markdown itself can produce.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+)
+
+// markdownWithRaw passes raw HTML through, for content that came from Canvas
+// in the first place and may contain embeds Markdown can't express.
+var markdownWithRaw = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+// ToHTML converts Markdown into HTML that is safe to post to Canvas.
+func ToHTML(source string) (string, error) {
+	return convert(markdown, source)
+}
+
+// ToHTMLWithRaw converts Markdown into HTML keeping any raw HTML, such as the
+// iframes ToMarkdown leaves in place. Canvas sanitizes it on save.
+func ToHTMLWithRaw(source string) (string, error) {
+	return convert(markdownWithRaw, source)
+}
+
+func convert(md goldmark.Markdown, source string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	}
+	return buf.String(), nil
+}
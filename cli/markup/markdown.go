@@ -0,0 +1,334 @@
+package markup
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// ToMarkdown converts Canvas HTML (page bodies, assignment descriptions) into
+// Markdown for editing. Elements Markdown can't express, like iframes and
+// embedded media, are kept as raw HTML so they survive a round trip through
+// ToHTMLWithRaw.
+func ToMarkdown(source string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(source), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	w := &markdownWriter{}
+	for _, n := range nodes {
+		w.block(n, "")
+	}
+
+	out := blankLines.ReplaceAllString(w.buf.String(), "\n\n")
+	return strings.TrimSpace(out) + "\n", nil
+}
+
+type markdownWriter struct {
+	buf bytes.Buffer
+}
+
+// block writes a block level node. prefix is prepended to every line, for
+// blockquotes and nested list items.
+func (w *markdownWriter) block(n *html.Node, prefix string) {
+	switch n.Type {
+	case html.TextNode:
+		if text := collapseSpace(n.Data); strings.TrimSpace(text) != "" {
+			w.paragraph(strings.TrimSpace(text), prefix)
+		}
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		w.paragraph(strings.Repeat("#", level)+" "+w.inlineChildren(n), prefix)
+	case atom.P:
+		if text := w.inlineChildren(n); text != "" {
+			w.paragraph(text, prefix)
+		}
+	case atom.Pre:
+		w.codeBlock(n, prefix)
+	case atom.Blockquote:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			w.block(c, prefix+"> ")
+		}
+		// A blank line without the marker ends the quote, otherwise the next
+		// paragraph would be pulled into it
+		w.buf.WriteString(strings.TrimRight(prefix, " ") + "\n")
+	case atom.Ul, atom.Ol:
+		w.list(n, prefix)
+		w.buf.WriteString(strings.TrimRight(prefix, " ") + "\n")
+	case atom.Hr:
+		w.paragraph("---", prefix)
+	case atom.Table:
+		w.table(n, prefix)
+	case atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Footer, atom.Body:
+		w.blockChildren(n, prefix)
+	case atom.Iframe, atom.Video, atom.Audio, atom.Object, atom.Embed, atom.Script, atom.Style, atom.Form:
+		w.paragraph(renderRaw(n), prefix)
+	default:
+		if text := w.inline(n); strings.TrimSpace(text) != "" {
+			w.paragraph(strings.TrimSpace(text), prefix)
+		}
+	}
+}
+
+// blockChildren groups runs of inline children into paragraphs and writes
+// block children as blocks.
+func (w *markdownWriter) blockChildren(n *html.Node, prefix string) {
+	var inline strings.Builder
+	flush := func() {
+		if text := trimBreaks(strings.TrimSpace(inline.String())); text != "" {
+			w.paragraph(text, prefix)
+		}
+		inline.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isBlock(c) {
+			flush()
+			w.block(c, prefix)
+			continue
+		}
+		inline.WriteString(w.inline(c))
+	}
+	flush()
+}
+
+func (w *markdownWriter) paragraph(text string, prefix string) {
+	for _, line := range strings.Split(text, "\n") {
+		w.buf.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+	w.buf.WriteString(strings.TrimRight(prefix, " ") + "\n")
+}
+
+func (w *markdownWriter) codeBlock(n *html.Node, prefix string) {
+	language := ""
+	code := n
+	if c := n.FirstChild; c != nil && c.DataAtom == atom.Code && c.NextSibling == nil {
+		code = c
+		for _, class := range strings.Fields(attr(c, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				language = strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+
+	w.paragraph("```"+language+"\n"+strings.TrimRight(textContent(code), "\n")+"\n```", prefix)
+}
+
+func (w *markdownWriter) list(n *html.Node, prefix string) {
+	index := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+		indent := strings.Repeat(" ", len(marker))
+
+		var text strings.Builder
+		var nested []*html.Node
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.DataAtom == atom.Ul || c.DataAtom == atom.Ol) {
+				nested = append(nested, c)
+				continue
+			}
+			if c.Type == html.ElementNode && c.DataAtom == atom.P {
+				text.WriteString(w.inlineChildren(c) + " ")
+				continue
+			}
+			text.WriteString(w.inline(c))
+		}
+
+		lines := strings.Split(trimBreaks(strings.TrimSpace(text.String())), "\n")
+		w.buf.WriteString(prefix + marker + lines[0] + "\n")
+		for _, line := range lines[1:] {
+			w.buf.WriteString(prefix + indent + line + "\n")
+		}
+		for _, list := range nested {
+			w.list(list, prefix+indent)
+		}
+	}
+}
+
+func (w *markdownWriter) table(n *html.Node, prefix string) {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.DataAtom == atom.Tr {
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+						row = append(row, strings.ReplaceAll(w.inlineChildren(cell), "|", `\|`))
+					}
+				}
+				rows = append(rows, row)
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+
+	if len(rows) == 0 {
+		return
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	w.paragraph(strings.TrimRight(b.String(), "\n"), prefix)
+}
+
+func (w *markdownWriter) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(w.inline(c))
+	}
+	return trimBreaks(strings.TrimSpace(b.String()))
+}
+
+func (w *markdownWriter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeMarkdown(collapseSpace(n.Data))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Strong, atom.B:
+		return wrapInline("**", w.inlineChildren(n))
+	case atom.Em, atom.I:
+		return wrapInline("_", w.inlineChildren(n))
+	case atom.Del, atom.S, atom.Strike:
+		return wrapInline("~~", w.inlineChildren(n))
+	case atom.Code:
+		return "`" + textContent(n) + "`"
+	case atom.Br:
+		return "\\\n"
+	case atom.A:
+		text := w.inlineChildren(n)
+		href := attr(n, "href")
+		if href == "" {
+			return text
+		}
+		if text == "" {
+			text = href
+		}
+		return fmt.Sprintf("[%s](%s)", text, href)
+	case atom.Img:
+		return fmt.Sprintf("![%s](%s)", attr(n, "alt"), attr(n, "src"))
+	case atom.Iframe, atom.Video, atom.Audio, atom.Object, atom.Embed, atom.Script, atom.Style:
+		return renderRaw(n)
+	}
+
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(w.inline(c))
+	}
+	return b.String()
+}
+
+func isBlock(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.P, atom.Pre, atom.Blockquote,
+		atom.Ul, atom.Ol, atom.Hr, atom.Table, atom.Div, atom.Section, atom.Article, atom.Main,
+		atom.Header, atom.Footer, atom.Iframe, atom.Video, atom.Audio, atom.Object, atom.Embed, atom.Form:
+		return true
+	}
+	return false
+}
+
+func wrapInline(marker string, text string) string {
+	if text == "" {
+		return ""
+	}
+	return marker + text + marker
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func renderRaw(n *html.Node) string {
+	var buf bytes.Buffer
+	if err := html.Render(&buf, n); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// trimBreaks drops the space left at the start of a line after a hard
+// break, where the HTML had a newline following the <br>.
+func trimBreaks(s string) string {
+	return strings.ReplaceAll(s, "\\\n ", "\\\n")
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+func collapseSpace(s string) string {
+	return whitespace.ReplaceAllString(s, " ")
+}
+
+var markdownSpecial = strings.NewReplacer(`*`, `\*`, `_`, `\_`, "`", "\\`", `[`, `\[`, `]`, `\]`)
+
+func escapeMarkdown(s string) string {
+	return markdownSpecial.Replace(s)
+}
@@ -0,0 +1,158 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+// Page bodies as Canvas's rich content editor saves them.
+var canvasPages = []struct {
+	name     string
+	html     string
+	markdown string
+}{
+	{
+		name:     "heading and external link",
+		html:     `<h2>Block 3: Recursion</h2><p>Read the <a href="https://example.com/notes" target="_blank" class="external" rel="noopener">notes</a> before class.</p>`,
+		markdown: "## Block 3: Recursion\n\nRead the [notes](https://example.com/notes) before class.\n",
+	},
+	{
+		name:     "emphasis",
+		html:     `<p><strong>Due:</strong> <em>Friday</em> at 5pm</p>`,
+		markdown: "**Due:** _Friday_ at 5pm\n",
+	},
+	{
+		name:     "nested and ordered lists",
+		html:     `<ul><li>Clone the repo</li><li>Run <code>npm test</code><ul><li>fix failures</li></ul></li></ul><ol><li>one</li><li>two</li></ol>`,
+		markdown: "- Clone the repo\n- Run `npm test`\n  - fix failures\n\n1. one\n2. two\n",
+	},
+	{
+		name:     "code block with language",
+		html:     "<pre><code class=\"language-js\">const x = 1;\nconsole.log(x);</code></pre>",
+		markdown: "```js\nconst x = 1;\nconsole.log(x);\n```\n",
+	},
+	{
+		name:     "markdown characters in text",
+		html:     `<p>Use snake_case and *stars* [brackets]</p>`,
+		markdown: "Use snake\\_case and \\*stars\\* \\[brackets\\]\n",
+	},
+	{
+		name:     "table",
+		html:     `<table><tbody><tr><th>Day</th><th>Topic</th></tr><tr><td>Mon</td><td>Loops | arrays</td></tr></tbody></table>`,
+		markdown: "| Day | Topic |\n| --- | --- |\n| Mon | Loops \\| arrays |\n",
+	},
+	{
+		name:     "blockquote",
+		html:     `<blockquote><p>Quoted</p></blockquote><p>After</p>`,
+		markdown: "> Quoted\n>\n\nAfter\n",
+	},
+	{
+		name:     "line break",
+		html:     "<p>line one<br>\nline two</p>",
+		markdown: "line one\\\nline two\n",
+	},
+	{
+		name:     "styled div",
+		html:     `<div style="padding: 10px;"><p style="color: #333;">Styled</p></div>`,
+		markdown: "Styled\n",
+	},
+	{
+		name:     "file link",
+		html:     `<p><a class="instructure_file_link" title="slides.pdf" href="https://canvas.example.com/courses/1/files/42/download?wrap=1" data-api-endpoint="https://canvas.example.com/api/v1/courses/1/files/42" data-api-returntype="File">slides.pdf</a></p>`,
+		markdown: "[slides.pdf](https://canvas.example.com/courses/1/files/42/download?wrap=1)\n",
+	},
+	{
+		name:     "embedded video",
+		html:     `<p><iframe src="https://www.youtube.com/embed/abc" width="560" height="315" allowfullscreen="allowfullscreen"></iframe></p>`,
+		markdown: "<iframe src=\"https://www.youtube.com/embed/abc\" width=\"560\" height=\"315\" allowfullscreen=\"allowfullscreen\"></iframe>\n",
+	},
+	{
+		name:     "image",
+		html:     `<p><img src="https://canvas.example.com/courses/1/files/7/preview" alt="diagram" width="400"></p>`,
+		markdown: "![diagram](https://canvas.example.com/courses/1/files/7/preview)\n",
+	},
+	{
+		name:     "rule",
+		html:     `<hr><p>End</p>`,
+		markdown: "---\n\nEnd\n",
+	},
+}
+
+func TestToMarkdown(t *testing.T) {
+	for _, tc := range canvasPages {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToMarkdown(tc.html)
+			if err != nil {
+				t.Fatalf("ToMarkdown: %v", err)
+			}
+			if got != tc.markdown {
+				t.Errorf("ToMarkdown(%q)\ngot:\n%s\nwant:\n%s", tc.html, got, tc.markdown)
+			}
+		})
+	}
+}
+
+// Saving an unedited page converts it to HTML and back; the Markdown must
+// come out the same or every save would show a spurious diff.
+func TestRoundTripIsStable(t *testing.T) {
+	for _, tc := range canvasPages {
+		t.Run(tc.name, func(t *testing.T) {
+			html, err := ToHTMLWithRaw(tc.markdown)
+			if err != nil {
+				t.Fatalf("ToHTMLWithRaw: %v", err)
+			}
+			got, err := ToMarkdown(html)
+			if err != nil {
+				t.Fatalf("ToMarkdown: %v", err)
+			}
+			if got != tc.markdown {
+				t.Errorf("round trip through %q\ngot:\n%s\nwant:\n%s", html, got, tc.markdown)
+			}
+		})
+	}
+}
+
+// The round trip keeps content but not presentation attributes, which is
+// why the editor refuses to save an unchanged item.
+func TestRoundTripDropsAttributes(t *testing.T) {
+	var source strings.Builder
+	for _, tc := range canvasPages {
+		source.WriteString(tc.html)
+	}
+
+	markdown, err := ToMarkdown(source.String())
+	if err != nil {
+		t.Fatalf("ToMarkdown: %v", err)
+	}
+	html, err := ToHTMLWithRaw(markdown)
+	if err != nil {
+		t.Fatalf("ToHTMLWithRaw: %v", err)
+	}
+
+	for _, kept := range []string{
+		`href="https://example.com/notes"`,
+		`href="https://canvas.example.com/courses/1/files/42/download?wrap=1"`,
+		`src="https://canvas.example.com/courses/1/files/7/preview"`,
+		`<iframe src="https://www.youtube.com/embed/abc"`,
+		`<code class="language-js">`,
+	} {
+		if !strings.Contains(html, kept) {
+			t.Errorf("round trip lost %s", kept)
+		}
+	}
+	for _, dropped := range []string{`target=`, `class="external"`, `data-api-endpoint=`, `style=`, `width="400"`} {
+		if strings.Contains(html, dropped) {
+			t.Errorf("round trip unexpectedly kept %s", dropped)
+		}
+	}
+}
+
+func TestToHTMLDropsRawHTML(t *testing.T) {
+	html, err := ToHTML("Hello <script>alert(1)</script>\n")
+	if err != nil {
+		t.Fatalf("ToHTML: %v", err)
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("ToHTML kept raw html: %s", html)
+	}
+}
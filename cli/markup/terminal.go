@@ -0,0 +1,250 @@
+package markup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	h1Style     = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color("5"))
+	h2Style     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	h3Style     = lipgloss.NewStyle().Bold(true)
+	boldStyle   = lipgloss.NewStyle().Bold(true)
+	italicStyle = lipgloss.NewStyle().Italic(true)
+	strikeStyle = lipgloss.NewStyle().Strikethrough(true)
+	codeStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	linkStyle   = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("4"))
+	dimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// Render turns Canvas HTML into styled text for the terminal, wrapped to
+// width columns.
+func Render(source string, width int) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(source), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	w := &terminalWriter{width: max(width, 20)}
+	for _, n := range nodes {
+		w.block(n, "")
+	}
+	return strings.TrimRight(strings.Join(w.lines, "\n"), "\n "), nil
+}
+
+type terminalWriter struct {
+	width int
+	lines []string
+}
+
+func (w *terminalWriter) block(n *html.Node, prefix string) {
+	switch n.Type {
+	case html.TextNode:
+		if text := strings.TrimSpace(collapseSpace(n.Data)); text != "" {
+			w.paragraph(text, prefix)
+		}
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1:
+		w.paragraph(h1Style.Render(strings.ToUpper(w.inlineChildren(n))), prefix)
+	case atom.H2:
+		w.paragraph(h2Style.Render(w.inlineChildren(n)), prefix)
+	case atom.H3, atom.H4, atom.H5, atom.H6:
+		w.paragraph(h3Style.Render(w.inlineChildren(n)), prefix)
+	case atom.P:
+		if text := w.inlineChildren(n); text != "" {
+			w.paragraph(text, prefix)
+		}
+	case atom.Pre:
+		for _, line := range strings.Split(strings.TrimRight(textContent(n), "\n"), "\n") {
+			w.lines = append(w.lines, prefix+dimStyle.Render("│ ")+codeStyle.Render(line))
+		}
+		w.lines = append(w.lines, prefix)
+	case atom.Blockquote:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			w.block(c, prefix+dimStyle.Render("┃ "))
+		}
+	case atom.Ul, atom.Ol:
+		w.list(n, prefix)
+		w.lines = append(w.lines, prefix)
+	case atom.Hr:
+		w.lines = append(w.lines, prefix+dimStyle.Render(strings.Repeat("─", max(0, w.width-ansi.StringWidth(prefix)))), prefix)
+	case atom.Table:
+		w.table(n, prefix)
+	case atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Footer, atom.Body:
+		w.blockChildren(n, prefix)
+	case atom.Iframe, atom.Video, atom.Audio, atom.Object, atom.Embed:
+		w.paragraph(dimStyle.Render(fmt.Sprintf("[embedded %s: %s]", n.Data, attr(n, "src"))), prefix)
+	case atom.Script, atom.Style:
+	default:
+		if text := strings.TrimSpace(w.inline(n)); text != "" {
+			w.paragraph(text, prefix)
+		}
+	}
+}
+
+func (w *terminalWriter) blockChildren(n *html.Node, prefix string) {
+	var inline strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(inline.String()); text != "" {
+			w.paragraph(text, prefix)
+		}
+		inline.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isBlock(c) {
+			flush()
+			w.block(c, prefix)
+			continue
+		}
+		inline.WriteString(w.inline(c))
+	}
+	flush()
+}
+
+// paragraph wraps text to the space left after prefix and ends it with a
+// blank line.
+func (w *terminalWriter) paragraph(text string, prefix string) {
+	w.wrapped(text, prefix, prefix)
+	w.lines = append(w.lines, prefix)
+}
+
+// wrapped wraps text, starting the first line with first and the rest with
+// rest, so list bullets hang.
+func (w *terminalWriter) wrapped(text string, first string, rest string) {
+	limit := max(w.width-ansi.StringWidth(first), 10)
+	for i, line := range strings.Split(ansi.Wrap(text, limit, ""), "\n") {
+		if i == 0 {
+			w.lines = append(w.lines, first+line)
+			continue
+		}
+		w.lines = append(w.lines, rest+line)
+	}
+}
+
+func (w *terminalWriter) list(n *html.Node, prefix string) {
+	index := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "• "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+		indent := strings.Repeat(" ", ansi.StringWidth(marker))
+
+		var text strings.Builder
+		var nested []*html.Node
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.DataAtom == atom.Ul || c.DataAtom == atom.Ol) {
+				nested = append(nested, c)
+				continue
+			}
+			if c.Type == html.ElementNode && c.DataAtom == atom.P {
+				text.WriteString(w.inlineChildren(c) + " ")
+				continue
+			}
+			text.WriteString(w.inline(c))
+		}
+
+		w.wrapped(strings.TrimSpace(text.String()), prefix+marker, prefix+indent)
+		for _, list := range nested {
+			w.list(list, prefix+indent)
+		}
+	}
+}
+
+func (w *terminalWriter) table(n *html.Node, prefix string) {
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.DataAtom == atom.Tr {
+				var cells []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					switch cell.DataAtom {
+					case atom.Th:
+						cells = append(cells, boldStyle.Render(w.inlineChildren(cell)))
+					case atom.Td:
+						cells = append(cells, w.inlineChildren(cell))
+					}
+				}
+				w.wrapped(strings.Join(cells, dimStyle.Render(" │ ")), prefix, prefix+"  ")
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	w.lines = append(w.lines, prefix)
+}
+
+func (w *terminalWriter) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(w.inline(c))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (w *terminalWriter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return collapseSpace(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Strong, atom.B:
+		return boldStyle.Render(w.inlineChildren(n))
+	case atom.Em, atom.I:
+		return italicStyle.Render(w.inlineChildren(n))
+	case atom.Del, atom.S, atom.Strike:
+		return strikeStyle.Render(w.inlineChildren(n))
+	case atom.Code:
+		return codeStyle.Render(textContent(n))
+	case atom.Br:
+		return "\n"
+	case atom.A:
+		text := w.inlineChildren(n)
+		href := attr(n, "href")
+		if href == "" || text == href {
+			return linkStyle.Render(text)
+		}
+		return linkStyle.Render(text) + " " + dimStyle.Render("("+href+")")
+	case atom.Img:
+		return dimStyle.Render(fmt.Sprintf("[image: %s]", attr(n, "alt")))
+	case atom.Iframe, atom.Video, atom.Audio, atom.Object, atom.Embed:
+		return dimStyle.Render(fmt.Sprintf("[embedded %s: %s]", n.Data, attr(n, "src")))
+	case atom.Script, atom.Style:
+		return ""
+	}
+
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(w.inline(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+// Package roster maps Canvas students to GitHub usernames and works out the
+// changes needed to bring the cohort team in line with the roster.
+package roster
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the mapping file format version.
+const CurrentVersion = 1
+
+type Entry struct {
+	CanvasID int    `yaml:"canvas_id"`
+	Name     string `yaml:"name,omitempty"`
+	GitHub   string `yaml:"github"`
+}
+
+// Mapping is the local file of GitHub usernames. Name is only there to make
+// the file readable; entries are matched on CanvasID.
+type Mapping struct {
+	Version  int     `yaml:"version"`
+	Students []Entry `yaml:"students"`
+}
+
+// DefaultPath is where the mapping lives unless GITHUB_USERNAMES is set.
+func DefaultPath() string {
+	if path := os.Getenv("GITHUB_USERNAMES"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "canvasInstructor", "github-usernames.yaml")
+}
+
+// Load reads the mapping at path. A missing file is an empty mapping.
+func Load(path string) (*Mapping, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Mapping{Version: CurrentVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GitHub username mapping: %w", err)
+	}
+	defer file.Close()
+
+	return Decode(file)
+}
+
+func Decode(r io.Reader) (*Mapping, error) {
+	var m Mapping
+	if err := yaml.NewDecoder(r).Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse GitHub username mapping: %w", err)
+	}
+	if m.Version > CurrentVersion {
+		return nil, fmt.Errorf("GitHub username mapping version %d is newer than supported version %d", m.Version, CurrentVersion)
+	}
+	m.Version = CurrentVersion
+
+	seen := map[int]bool{}
+	for _, e := range m.Students {
+		if e.CanvasID == 0 || strings.TrimSpace(e.GitHub) == "" {
+			return nil, fmt.Errorf("mapping entry %q needs a canvas_id and a github username", e.Name)
+		}
+		if seen[e.CanvasID] {
+			return nil, fmt.Errorf("canvas_id %d is mapped more than once", e.CanvasID)
+		}
+		seen[e.CanvasID] = true
+	}
+	return &m, nil
+}
+
+// Usernames returns the GitHub username for each Canvas user id.
+func (m *Mapping) Usernames() map[int]string {
+	usernames := make(map[int]string, len(m.Students))
+	for _, e := range m.Students {
+		usernames[e.CanvasID] = strings.TrimSpace(e.GitHub)
+	}
+	return usernames
+}
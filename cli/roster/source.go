@@ -0,0 +1,76 @@
+package roster
+
+import (
+	"fmt"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+)
+
+// Where GitHub usernames come from.
+const (
+	SourceFile   = "file"
+	SourceCanvas = "canvas"
+)
+
+// DefaultColumn is the custom gradebook column read by SourceCanvas.
+const DefaultColumn = "GitHub"
+
+// LoadUsernames reads usernames from the mapping file at path or from the
+// gradebook column, depending on source.
+func LoadUsernames(client *api.Client, source string, path string, column string) (map[int]string, error) {
+	switch source {
+	case SourceFile:
+		mapping, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(mapping.Students) == 0 {
+			return nil, fmt.Errorf("no GitHub usernames in %s", path)
+		}
+		return mapping.Usernames(), nil
+	case SourceCanvas:
+		entries, err := client.GetGitHubUsernames(column)
+		if err != nil {
+			return nil, err
+		}
+		usernames := make(map[int]string, len(entries))
+		for _, e := range entries {
+			usernames[e.UserID] = e.Username
+		}
+		return usernames, nil
+	}
+	return nil, fmt.Errorf("unknown username source %q (expected file or canvas)", source)
+}
+
+// BuildPlan fetches the roster and the team's membership and diffs them.
+func BuildPlan(client *api.Client, gh *github.Client, team api.GitHubTeam, usernames map[int]string) (Plan, error) {
+	enrollments, err := client.GetCourseEnrollments()
+	if err != nil {
+		return Plan{}, err
+	}
+	members, err := gh.ListTeamMembers(team.Organization, team.Team, "member")
+	if err != nil {
+		return Plan{}, err
+	}
+	pending, err := gh.ListTeamInvitations(team.Organization, team.Team)
+	if err != nil {
+		return Plan{}, err
+	}
+	return Diff(enrollments, usernames, members, pending), nil
+}
+
+// Apply makes each change in turn, reporting the outcome of every change to
+// done so callers can show progress. It carries on past failures.
+func Apply(gh *github.Client, team api.GitHubTeam, changes []Change, done func(Change, error)) {
+	for _, change := range changes {
+		var err error
+		switch change.Action {
+		case ActionInvite:
+			err = gh.AddTeamMember(team.Organization, team.Team, change.Username)
+		case ActionRemove:
+			err = gh.RemoveTeamMember(team.Organization, team.Team, change.Username)
+		}
+		done(change, err)
+	}
+}
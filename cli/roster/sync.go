@@ -0,0 +1,99 @@
+package roster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+type Action string
+
+const (
+	ActionInvite Action = "invite"
+	ActionRemove Action = "remove"
+)
+
+type Change struct {
+	Action   Action `json:"action"`
+	Username string `json:"username"`
+	// Student is empty when removing someone who isn't on the roster.
+	Student string `json:"student,omitempty"`
+}
+
+// Plan is what it takes to make the team match the roster.
+type Plan struct {
+	Changes []Change `json:"changes"`
+	// InSync are students already on the team or with a pending invitation.
+	InSync []string `json:"in_sync"`
+	// Unmapped are students without a GitHub username, who can't be added.
+	Unmapped []string `json:"unmapped"`
+	// Held are members who would be removed, kept while any student is
+	// unmapped since they may be that student's account.
+	Held []string `json:"held"`
+}
+
+// Diff compares active and invited students against the team. members should
+// only hold the team's plain members so maintainers (instructors) are never
+// removed; pending invitations count as members for the purpose of inviting.
+// Nobody is removed while a student has no GitHub username.
+func Diff(students []api.Enrollment, usernames map[int]string, members []string, pending []string) Plan {
+	var plan Plan
+
+	onTeam := map[string]bool{}
+	for _, login := range append(append([]string{}, members...), pending...) {
+		onTeam[strings.ToLower(login)] = true
+	}
+
+	wanted := map[string]bool{}
+	for _, student := range students {
+		if student.Type != "StudentEnrollment" || (student.State != "active" && student.State != "invited") {
+			continue
+		}
+		username := usernames[student.User.ID]
+		if username == "" {
+			plan.Unmapped = append(plan.Unmapped, student.User.Name)
+			continue
+		}
+		wanted[strings.ToLower(username)] = true
+		if onTeam[strings.ToLower(username)] {
+			plan.InSync = append(plan.InSync, student.User.Name)
+			continue
+		}
+		plan.Changes = append(plan.Changes, Change{Action: ActionInvite, Username: username, Student: student.User.Name})
+	}
+
+	for _, login := range members {
+		if wanted[strings.ToLower(login)] {
+			continue
+		}
+		if len(plan.Unmapped) > 0 {
+			plan.Held = append(plan.Held, login)
+			continue
+		}
+		plan.Changes = append(plan.Changes, Change{Action: ActionRemove, Username: login})
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		if plan.Changes[i].Action != plan.Changes[j].Action {
+			return plan.Changes[i].Action == ActionInvite
+		}
+		return strings.ToLower(plan.Changes[i].Username) < strings.ToLower(plan.Changes[j].Username)
+	})
+	sort.Strings(plan.InSync)
+	sort.Strings(plan.Unmapped)
+	sort.Slice(plan.Held, func(i, j int) bool { return strings.ToLower(plan.Held[i]) < strings.ToLower(plan.Held[j]) })
+	return plan
+}
+
+// Count returns the number of invitations and removals in the plan.
+func (p Plan) Count() (invites int, removals int) {
+	for _, c := range p.Changes {
+		if c.Action == ActionInvite {
+			invites++
+		} else {
+			removals++
+		}
+	}
+	return invites, removals
+}
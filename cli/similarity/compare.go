@@ -0,0 +1,151 @@
+// Package similarity flags pairs of students whose code shares unusually
+// many winnowed fingerprints, for an instructor to review.
+package similarity
+
+import (
+	"sort"
+)
+
+const (
+	DefaultThreshold = 0.3
+	DefaultMaxShare  = 0.5
+)
+
+type Student struct {
+	Name         string
+	Fingerprints Fingerprints
+}
+
+// Match is a pair of line ranges with the same code.
+type Match struct {
+	A Span `json:"a"`
+	B Span `json:"b"`
+}
+
+type Pair struct {
+	A      string `json:"a"`
+	B      string `json:"b"`
+	Shared int    `json:"shared"`
+	// Similarity is the shared fingerprints over the smaller student's
+	// fingerprints, so copying a small part of a big project still shows.
+	Similarity float64 `json:"similarity"`
+	Matches    []Match `json:"matches"`
+}
+
+type Options struct {
+	// Threshold is the lowest similarity reported.
+	Threshold float64
+	// MaxShare drops fingerprints found in more than this fraction of
+	// students, which are usually common idioms or hints from class. It is
+	// only applied with four or more students.
+	MaxShare float64
+}
+
+// Compare finds the pairs of students at or above the threshold, most
+// similar first. Fingerprints in starter are ignored.
+func Compare(students []Student, starter Fingerprints, opts Options) []Pair {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+	if opts.MaxShare <= 0 {
+		opts.MaxShare = DefaultMaxShare
+	}
+
+	index := map[uint64][]int{}
+	counts := make([]int, len(students))
+	for i, student := range students {
+		for hash := range student.Fingerprints {
+			if _, ok := starter[hash]; ok {
+				continue
+			}
+			index[hash] = append(index[hash], i)
+			counts[i]++
+		}
+	}
+
+	type key struct{ a, b int }
+	shared := map[key][]uint64{}
+	limit := int(opts.MaxShare * float64(len(students)))
+	for hash, holders := range index {
+		if len(holders) < 2 || (len(students) >= 4 && len(holders) > limit) {
+			continue
+		}
+		for x := 0; x < len(holders); x++ {
+			for y := x + 1; y < len(holders); y++ {
+				k := key{holders[x], holders[y]}
+				shared[k] = append(shared[k], hash)
+			}
+		}
+	}
+
+	var pairs []Pair
+	for k, hashes := range shared {
+		smaller := min(counts[k.a], counts[k.b])
+		if smaller == 0 {
+			continue
+		}
+		similarity := float64(len(hashes)) / float64(smaller)
+		if similarity < opts.Threshold {
+			continue
+		}
+
+		a, b := students[k.a], students[k.b]
+		var matches []Match
+		for _, hash := range hashes {
+			matches = append(matches, Match{A: a.Fingerprints[hash][0], B: b.Fingerprints[hash][0]})
+		}
+		pairs = append(pairs, Pair{
+			A:          a.Name,
+			B:          b.Name,
+			Shared:     len(hashes),
+			Similarity: similarity,
+			Matches:    mergeMatches(matches),
+		})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		return pairs[i].A+pairs[i].B < pairs[j].A+pairs[j].B
+	})
+	return pairs
+}
+
+// mergeMatches joins matches that continue each other in both files into
+// single ranges.
+func mergeMatches(matches []Match) []Match {
+	sort.Slice(matches, func(i, j int) bool {
+		x, y := matches[i], matches[j]
+		if x.A.File != y.A.File {
+			return x.A.File < y.A.File
+		}
+		if x.B.File != y.B.File {
+			return x.B.File < y.B.File
+		}
+		if x.A.StartLine != y.A.StartLine {
+			return x.A.StartLine < y.A.StartLine
+		}
+		return x.B.StartLine < y.B.StartLine
+	})
+
+	var merged []Match
+	for _, m := range matches {
+		if n := len(merged); n > 0 && continues(merged[n-1], m) {
+			last := &merged[n-1]
+			last.A.EndLine = max(last.A.EndLine, m.A.EndLine)
+			last.B.StartLine = min(last.B.StartLine, m.B.StartLine)
+			last.B.EndLine = max(last.B.EndLine, m.B.EndLine)
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+func continues(prev Match, next Match) bool {
+	const gap = 2
+	return prev.A.File == next.A.File && prev.B.File == next.B.File &&
+		next.A.StartLine <= prev.A.EndLine+gap &&
+		next.B.StartLine <= prev.B.EndLine+gap && next.B.EndLine >= prev.B.StartLine-gap
+}
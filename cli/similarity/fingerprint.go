@@ -0,0 +1,131 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultK is the number of tokens in each hashed k-gram; matches
+	// shorter than this are never seen.
+	DefaultK = 12
+	// DefaultWindow is the winnowing window; any match of at least
+	// K+Window-1 tokens is guaranteed to be found.
+	DefaultWindow = 8
+)
+
+// Span is where a fingerprint came from.
+type Span struct {
+	File      string
+	StartLine int
+	EndLine   int
+}
+
+// Fingerprints maps a k-gram hash to the places it was selected.
+type Fingerprints map[uint64][]Span
+
+// sourceExtensions are the files worth comparing. Markup and config are left
+// out since they are mostly boilerplate.
+var sourceExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".py": true, ".go": true, ".java": true, ".rb": true, ".php": true,
+	".c": true, ".h": true, ".cpp": true, ".hpp": true, ".cs": true,
+	".rs": true, ".swift": true, ".kt": true, ".sql": true,
+}
+
+// skipDirs hold dependencies and build output rather than student work.
+var skipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true, "build": true,
+	"coverage": true, "__pycache__": true, ".venv": true, "venv": true, "target": true,
+}
+
+// maxFileSize skips generated and minified bundles.
+const maxFileSize = 256 * 1024
+
+// FingerprintDir winnows every source file under dir. File names in the
+// spans are relative to dir.
+func FingerprintDir(dir string, k int, window int) (Fingerprints, error) {
+	fps := Fingerprints{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !sourceExtensions[strings.ToLower(filepath.Ext(path))] || strings.Contains(d.Name(), ".min.") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxFileSize {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		for hash, span := range Winnow(Tokenize(string(data)), k, window) {
+			for _, s := range span {
+				s.File = rel
+				fps[hash] = append(fps[hash], s)
+			}
+		}
+		return nil
+	})
+	return fps, err
+}
+
+// Winnow hashes every k-gram of tokens and keeps the minimum hash in each
+// window of hashes, as in Schleimer, Wilkerson and Aiken's winnowing.
+func Winnow(tokens []Token, k int, window int) Fingerprints {
+	fps := Fingerprints{}
+	if len(tokens) < k {
+		return fps
+	}
+
+	hashes := make([]uint64, len(tokens)-k+1)
+	for i := range hashes {
+		h := fnv.New64a()
+		for _, t := range tokens[i : i+k] {
+			h.Write([]byte(t.Text))
+			h.Write([]byte{0})
+		}
+		hashes[i] = h.Sum64()
+	}
+
+	add := func(i int) {
+		fps[hashes[i]] = append(fps[hashes[i]], Span{StartLine: tokens[i].Line, EndLine: tokens[i+k-1].Line})
+	}
+
+	if len(hashes) <= window {
+		add(minIndex(hashes, 0, len(hashes)))
+		return fps
+	}
+
+	last := -1
+	for start := 0; start+window <= len(hashes); start++ {
+		if i := minIndex(hashes, start, start+window); i != last {
+			add(i)
+			last = i
+		}
+	}
+	return fps
+}
+
+// minIndex returns the rightmost minimum in hashes[start:end].
+func minIndex(hashes []uint64, start int, end int) int {
+	best := start
+	for i := start; i < end; i++ {
+		if hashes[i] <= hashes[best] {
+			best = i
+		}
+	}
+	return best
+}
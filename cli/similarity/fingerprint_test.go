@@ -0,0 +1,97 @@
+package similarity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Token
+	}{
+		{
+			name: "identifiers and literals are normalized",
+			src:  `total = add(price, 2) + "tax"`,
+			want: []Token{{"id", 1}, {"=", 1}, {"id", 1}, {"(", 1}, {"id", 1}, {",", 1}, {"n", 1}, {")", 1}, {"+", 1}, {"s", 1}},
+		},
+		{
+			name: "keywords survive",
+			src:  "if x {\n  return nil\n}",
+			want: []Token{{"if", 1}, {"id", 1}, {"{", 1}, {"return", 2}, {"nil", 2}, {"}", 3}},
+		},
+		{
+			name: "comments are dropped but keep line numbers",
+			src:  "// note\n/* a\nb */ x # trailing\ny",
+			want: []Token{{"id", 3}, {"id", 4}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Tokenize(tc.src); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Tokenize(%q) = %v, want %v", tc.src, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWinnow(t *testing.T) {
+	original := Tokenize("for (i = 0; i < n; i++) { sum += values[i]; }\nreturn sum;")
+	renamed := Tokenize("for (j = 0; j < count; j++) { total += xs[j]; }\nreturn total;")
+	different := Tokenize("while (queue.length) { node = queue.shift(); visit(node); }")
+
+	tests := []struct {
+		name      string
+		a, b      []Token
+		k, window int
+		shared    bool
+	}{
+		{name: "renamed variables still match", a: original, b: renamed, k: 5, window: 4, shared: true},
+		{name: "different code shares nothing", a: original, b: different, k: 5, window: 4, shared: false},
+		{name: "shorter than k", a: original[:3], b: original[:3], k: 5, window: 4, shared: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Winnow(tc.a, tc.k, tc.window)
+			b := Winnow(tc.b, tc.k, tc.window)
+			shared := false
+			for hash := range a {
+				if _, ok := b[hash]; ok {
+					shared = true
+				}
+			}
+			if shared != tc.shared {
+				t.Errorf("shared fingerprints = %v, want %v", shared, tc.shared)
+			}
+		})
+	}
+}
+
+func TestWinnowGuarantee(t *testing.T) {
+	// Any run of at least k+window-1 matching tokens must share a
+	// fingerprint, wherever it sits in the surrounding code.
+	k, window := 4, 3
+	common := Tokenize("a = b * c + d(e, f) - g")[:k+window-1]
+	before := Tokenize("if x { y() } else { z() }")
+	after := Tokenize("while (q) { r(); }")
+
+	a := Winnow(append(append([]Token{}, before...), common...), k, window)
+	b := Winnow(append(append([]Token{}, common...), after...), k, window)
+	for hash := range a {
+		if _, ok := b[hash]; ok {
+			return
+		}
+	}
+	t.Errorf("no shared fingerprint for a %d token match", len(common))
+}
+
+func TestWinnowSelectsOnePerWindow(t *testing.T) {
+	tokens := Tokenize("+ - * / ( ) { } [ ]")
+	fps := Winnow(tokens, 1, 20)
+	if len(fps) != 1 {
+		t.Errorf("Winnow() kept %d fingerprints for a single window, want 1", len(fps))
+	}
+}
@@ -0,0 +1,94 @@
+package similarity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token is a normalized source token and the line it came from.
+type Token struct {
+	Text string
+	Line int
+}
+
+// keywords survive normalization so structure still counts; every other
+// identifier becomes "id" so renaming variables doesn't hide a copy.
+var keywords = map[string]bool{}
+
+func init() {
+	for _, k := range strings.Fields(`
+		if else for while do switch case default break continue return function
+		func def class struct interface type var let const new delete try catch
+		finally throw throws raise except import from export package async await
+		yield lambda in of is not and or true false null nil None True False
+		this self super public private protected static void int string bool
+		float double char long go defer range map select chan elif with as pass`) {
+		keywords[k] = true
+	}
+}
+
+// Tokenize splits source into normalized tokens. Comments and whitespace are
+// dropped, identifiers other than keywords become "id", numbers "n" and
+// string literals "s". It is deliberately language agnostic: good enough for
+// the C-like and scripting languages students write in.
+func Tokenize(src string) []Token {
+	var tokens []Token
+	runes := []rune(src)
+	line := 1
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+		case r == '"' || r == '\'' || r == '`':
+			start := line
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' {
+					i++
+				} else if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i++
+			tokens = append(tokens, Token{Text: "s", Line: start})
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			word := string(runes[start:i])
+			if !keywords[word] {
+				word = "id"
+			}
+			tokens = append(tokens, Token{Text: word, Line: line})
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, Token{Text: "n", Line: line})
+		default:
+			tokens = append(tokens, Token{Text: string(r), Line: line})
+			i++
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,216 @@
+// Package templates reads lesson templates: a lesson header and the items
+// under it, with {{placeholders}} filled in when a lesson is created.
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a lesson: the first item is its header, the rest are indented
+// under it unless they set their own indent.
+type Template struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Items       []Item `yaml:"items"`
+}
+
+type Item struct {
+	Type            string   `yaml:"type"`
+	Title           string   `yaml:"title"`
+	Indent          *int     `yaml:"indent,omitempty"`
+	Body            string   `yaml:"body,omitempty"`
+	Description     string   `yaml:"description,omitempty"`
+	Points          float64  `yaml:"points,omitempty"`
+	SubmissionTypes []string `yaml:"submission_types,omitempty"`
+	URL             string   `yaml:"url,omitempty"`
+	NewTab          *bool    `yaml:"new_tab,omitempty"`
+}
+
+// itemTypes are the module items a template can create.
+var itemTypes = []string{"SubHeader", "Page", "Assignment", "ExternalUrl"}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// block is the program's standard block, used when there are no templates.
+const block = `name: Block
+description: Header, lecture page, workshop assignment and GitHub exercise
+items:
+  - type: SubHeader
+    title: "Block {{block}}: {{topic}}"
+  - type: Page
+    title: "Block {{block}} Lecture: {{topic}}"
+    body: "<h2>{{topic}}</h2>"
+  - type: Assignment
+    title: "Block {{block}} Workshop: {{topic}}"
+    description: "<p>Submit the link to your pull request.</p>"
+    points: 10
+  - type: ExternalUrl
+    title: "Block {{block}} Exercise"
+    url: "{{repo}}"
+`
+
+// DefaultDir is where templates live unless LESSON_TEMPLATES is set.
+func DefaultDir() string {
+	if dir := os.Getenv("LESSON_TEMPLATES"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "canvasInstructor", "templates")
+}
+
+// LoadAll reads every .yaml template in dir, by name. A missing or empty
+// directory gives the standard block template.
+func LoadAll(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		t, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", path, err)
+		}
+		if t.Name == "" {
+			t.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		templates = append(templates, t)
+	}
+
+	if len(templates) == 0 {
+		t, err := Parse([]byte(block))
+		if err != nil {
+			return nil, err
+		}
+		return []Template{t}, nil
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return strings.ToLower(templates[i].Name) < strings.ToLower(templates[j].Name)
+	})
+	return templates, nil
+}
+
+func Parse(data []byte) (Template, error) {
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return Template{}, fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := t.Validate(); err != nil {
+		return Template{}, err
+	}
+	return t, nil
+}
+
+func (t Template) Validate() error {
+	if len(t.Items) == 0 {
+		return fmt.Errorf("no items")
+	}
+	for i, item := range t.Items {
+		if !contains(itemTypes, item.Type) {
+			return fmt.Errorf("item %d: type must be one of %s, got %q", i+1, strings.Join(itemTypes, ", "), item.Type)
+		}
+		if item.Title == "" {
+			return fmt.Errorf("item %d: missing title", i+1)
+		}
+		if item.Type == "ExternalUrl" && item.URL == "" {
+			return fmt.Errorf("item %d: ExternalUrl needs a url", i+1)
+		}
+		if item.Indent != nil && (*item.Indent < 0 || *item.Indent > 5) {
+			return fmt.Errorf("item %d: indent must be between 0 and 5", i+1)
+		}
+	}
+	if indent := t.Items[0].Indent; indent != nil && *indent != 0 {
+		return fmt.Errorf("the first item is the lesson header and can't be indented")
+	}
+	return nil
+}
+
+// Placeholders are the names the template uses, in the order they appear.
+func (t Template) Placeholders() []string {
+	var names []string
+	for _, item := range t.Items {
+		for _, field := range []string{item.Title, item.Body, item.Description, item.URL} {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(field, -1) {
+				if !contains(names, match[1]) {
+					names = append(names, match[1])
+				}
+			}
+		}
+	}
+	return names
+}
+
+// Fill replaces the placeholders with values and returns the items to
+// create, in order.
+func (t Template) Fill(values map[string]string) ([]api.NewModuleItem, error) {
+	var missing []string
+	for _, name := range t.Placeholders() {
+		if strings.TrimSpace(values[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing values for %s", strings.Join(missing, ", "))
+	}
+
+	fill := func(s string) string {
+		return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := placeholderPattern.FindStringSubmatch(match)[1]
+			return strings.TrimSpace(values[name])
+		})
+	}
+
+	items := make([]api.NewModuleItem, 0, len(t.Items))
+	for i, item := range t.Items {
+		indent := 1
+		if i == 0 {
+			indent = 0
+		}
+		if item.Indent != nil {
+			indent = *item.Indent
+		}
+		items = append(items, api.NewModuleItem{
+			Type:            item.Type,
+			Title:           fill(item.Title),
+			Indent:          indent,
+			Body:            fill(item.Body),
+			Description:     fill(item.Description),
+			Points:          item.Points,
+			SubmissionTypes: item.SubmissionTypes,
+			URL:             fill(item.URL),
+			NewTab:          item.NewTab,
+		})
+	}
+	return items, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
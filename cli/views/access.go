@@ -0,0 +1,243 @@
+package views
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/content"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const accessPageSize = 15
+
+// GitHubAccessView audits the cohort team's access to every GitHub repository
+// the course links to, and lets us grant or revoke it per repository.
+type GitHubAccessView struct {
+	team     api.GitHubTeam
+	access   []github.Access
+	selected int
+	loading  string
+	confirm  bool
+	status   string
+	err      error
+}
+
+func NewGitHubAccessView() *GitHubAccessView {
+	log := logger.With("component", "github_access_view")
+	log.Info("Creating new GitHub access view")
+	return &GitHubAccessView{loading: "Auditing GitHub repository access"}
+}
+
+func (v *GitHubAccessView) Init() tea.Cmd {
+	return v.audit
+}
+
+func (v *GitHubAccessView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "github_access_view")
+
+	if v.confirm {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "y":
+				v.confirm = false
+				ref := v.access[v.selected].RepoRef
+				v.status = fmt.Sprintf("Revoking access to %s...", ref.Repo)
+				return v, v.revoke(ref)
+			case "n", "esc":
+				v.confirm = false
+			}
+		}
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.access)-1 {
+				v.selected++
+			}
+		case "g":
+			if v.loading == "" && len(v.access) > 0 && v.access[v.selected].Exists {
+				ref := v.access[v.selected].RepoRef
+				v.status = fmt.Sprintf("Granting access to %s...", ref.Repo)
+				return v, v.grant(ref)
+			}
+		case "x":
+			if v.loading == "" && len(v.access) > 0 && v.access[v.selected].Permission != "" {
+				v.confirm = true
+				v.status = ""
+			}
+		case "r":
+			if v.loading == "" {
+				v.loading = "Auditing GitHub repository access"
+				v.status = ""
+				return v, v.audit
+			}
+		case "esc":
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		}
+	case accessAuditedMsg:
+		log.Info("Audited repository access", "count", len(msg.access))
+		v.team = msg.team
+		v.access = msg.access
+		v.loading = ""
+		v.selected = 0
+	case accessChangedMsg:
+		log.Info("Changed repository access", "repository", msg.access.Repo.String(), "status", msg.access.Status())
+		for i := range v.access {
+			if v.access[i].Repo == msg.access.Repo {
+				v.access[i] = msg.access
+			}
+		}
+		v.status = msg.status
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.loading = ""
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *GitHubAccessView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+
+	if v.loading != "" {
+		return v.loading + "..."
+	}
+
+	counts := map[github.AccessStatus]int{}
+	for _, a := range v.access {
+		counts[a.Status()]++
+	}
+	s := fmt.Sprintf("GitHub access for team %s/%s\n", v.team.Organization, github.TeamSlug(v.team.Team))
+	s += fmt.Sprintf("%d granted, %d public, %d without access, %d missing, %d errors\n\n",
+		counts[github.AccessGranted],
+		counts[github.AccessPublic],
+		counts[github.AccessNone],
+		counts[github.AccessMissing],
+		counts[github.AccessError],
+	)
+
+	if len(v.access) == 0 {
+		s += "The course doesn't link to any GitHub repositories.\n"
+	}
+	start, end := pageBounds(v.selected, len(v.access), accessPageSize)
+	for i := start; i < end; i++ {
+		a := v.access[i]
+		cursor := " "
+		if v.selected == i {
+			cursor = ">"
+		}
+		status := string(a.Status())
+		if a.Permission != "" {
+			status += " (" + a.Permission + ")"
+		}
+		s += fmt.Sprintf("%s %-50s %s\n", cursor, truncate(a.Repo.String(), 50), status)
+	}
+
+	if len(v.access) > 0 {
+		a := v.access[v.selected]
+		s += "\n"
+		if a.Archived {
+			s += "Archived repository\n"
+		}
+		if a.Error != "" {
+			s += fmt.Sprintf("Error:     %s\n", a.Error)
+		}
+		s += fmt.Sprintf("Linked in: %s\n", firstN(a.Sources, 3))
+	}
+
+	if v.confirm {
+		s += fmt.Sprintf("\nRevoke %s access to %s? (y/n)", github.TeamSlug(v.team.Team), v.access[v.selected].Repo)
+		return s
+	}
+
+	if v.status != "" {
+		s += "\n" + v.status + "\n"
+	}
+	s += "\nPress g to grant pull access, x to revoke, r to refresh, esc to go back, q to quit."
+	return s
+}
+
+func (v *GitHubAccessView) audit() tea.Msg {
+	log := logger.With("component", "github_access_view", "action", "audit")
+
+	gh, err := github.NewClientFromEnv()
+	if err != nil {
+		return errMsg(err)
+	}
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	team, err := client.GetGitHubTeam()
+	if err != nil {
+		log.Error("Failed to fetch GitHub team", "error", err)
+		return errMsg(err)
+	}
+	refs, err := content.CourseRepos(client)
+	if err != nil {
+		log.Error("Failed to collect repositories", "error", err)
+		return errMsg(err)
+	}
+
+	return accessAuditedMsg{
+		team:   team,
+		access: gh.Audit(team.Organization, team.Team, refs, 4),
+	}
+}
+
+func (v *GitHubAccessView) grant(ref github.RepoRef) tea.Cmd {
+	team := v.team
+	return func() tea.Msg {
+		gh, err := github.NewClientFromEnv()
+		if err != nil {
+			return errMsg(err)
+		}
+		status := fmt.Sprintf("Granted pull access to %s", ref.Repo)
+		if err := gh.AddTeamRepository(team.Organization, team.Team, ref.Repo, "pull"); err != nil {
+			status = err.Error()
+		}
+		return accessChangedMsg{access: gh.CheckAccess(team.Organization, team.Team, ref), status: status}
+	}
+}
+
+func (v *GitHubAccessView) revoke(ref github.RepoRef) tea.Cmd {
+	team := v.team
+	return func() tea.Msg {
+		gh, err := github.NewClientFromEnv()
+		if err != nil {
+			return errMsg(err)
+		}
+		status := fmt.Sprintf("Revoked access to %s", ref.Repo)
+		if err := gh.RemoveTeamRepository(team.Organization, team.Team, ref.Repo); err != nil {
+			status = err.Error()
+		}
+		return accessChangedMsg{access: gh.CheckAccess(team.Organization, team.Team, ref), status: status}
+	}
+}
+
+// Message types
+type accessAuditedMsg struct {
+	team   api.GitHubTeam
+	access []github.Access
+}
+
+type accessChangedMsg struct {
+	access github.Access
+	status string
+}
@@ -0,0 +1,233 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/editor"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/markup"
+)
+
+type AnnouncementView struct {
+	draftPath  string
+	body       string
+	focus      int
+	submitting bool
+	status     string
+	err        error
+	// For title and scheduling input
+	titleInput  textinput.Model
+	postAtInput textinput.Model
+}
+
+func NewAnnouncementView() *AnnouncementView {
+	log := logger.With("component", "announcement_view")
+	log.Info("Creating new announcement view")
+
+	ti := textinput.New()
+	ti.Placeholder = "Announcement title"
+	ti.CharLimit = 255
+	ti.Width = 60
+	ti.Focus()
+
+	pi := textinput.New()
+	pi.Placeholder = "YYYY-MM-DD HH:MM (blank to post now)"
+	pi.CharLimit = 16
+	pi.Width = 40
+
+	return &AnnouncementView{
+		titleInput:  ti,
+		postAtInput: pi,
+	}
+}
+
+func (v *AnnouncementView) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (v *AnnouncementView) CapturingInput() bool {
+	return true
+}
+
+func (v *AnnouncementView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "announcement_view")
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if v.submitting {
+			return v, nil
+		}
+		switch msg.String() {
+		case "esc":
+			log.Info("Returning to home view")
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		case "tab", "shift+tab":
+			v.focus = 1 - v.focus
+			if v.focus == 0 {
+				v.postAtInput.Blur()
+				v.titleInput.Focus()
+			} else {
+				v.titleInput.Blur()
+				v.postAtInput.Focus()
+			}
+			return v, nil
+		case "ctrl+e":
+			return v, v.openEditor()
+		case "ctrl+s":
+			req, err := v.request()
+			if err != nil {
+				v.err = err
+				return v, nil
+			}
+			v.err = nil
+			v.submitting = true
+			return v, v.createAnnouncement(req)
+		}
+	case editorFinishedMsg:
+		if msg.err != nil {
+			log.Error("Editor exited with error", "error", msg.err)
+			v.err = msg.err
+			return v, nil
+		}
+		body, err := os.ReadFile(v.draftPath)
+		if err != nil {
+			v.err = fmt.Errorf("failed to read draft: %w", err)
+			return v, nil
+		}
+		v.body = string(body)
+		v.err = nil
+		return v, nil
+	case announcementCreatedMsg:
+		log.Info("Announcement created")
+		v.submitting = false
+		v.status = string(msg)
+		os.Remove(v.draftPath)
+		v.draftPath = ""
+		v.body = ""
+		v.titleInput.SetValue("")
+		v.postAtInput.SetValue("")
+		return v, nil
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.submitting = false
+		v.err = msg
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	if v.focus == 0 {
+		v.titleInput, cmd = v.titleInput.Update(msg)
+	} else {
+		v.postAtInput, cmd = v.postAtInput.Update(msg)
+	}
+	return v, cmd
+}
+
+func (v *AnnouncementView) View() string {
+	s := "New Announcement\n"
+	s += "================\n\n"
+	s += "Title:\n" + v.titleInput.View() + "\n\n"
+	s += "Post at:\n" + v.postAtInput.View() + "\n\n"
+
+	s += "Body (Markdown):\n"
+	if strings.TrimSpace(v.body) == "" {
+		s += "  (empty, press Ctrl+E to write it in $EDITOR)\n"
+	} else {
+		lines := strings.Split(strings.TrimRight(v.body, "\n"), "\n")
+		for i, line := range lines {
+			if i == 10 {
+				s += fmt.Sprintf("  ... %d more lines\n", len(lines)-10)
+				break
+			}
+			s += "  │ " + line + "\n"
+		}
+	}
+	s += "\n"
+
+	if v.submitting {
+		s += "Posting announcement...\n\n"
+	}
+	if v.err != nil {
+		s += fmt.Sprintf("Error: %v\n\n", v.err)
+	} else if v.status != "" {
+		s += v.status + "\n\n"
+	}
+
+	s += "Tab (next field), Ctrl+E (edit body), Ctrl+S (post), Esc (back)"
+	return s
+}
+
+func (v *AnnouncementView) openEditor() tea.Cmd {
+	if v.draftPath == "" {
+		path, err := editor.WriteTemp("announcement-*.md", v.body)
+		if err != nil {
+			v.err = err
+			return nil
+		}
+		v.draftPath = path
+	}
+
+	return tea.ExecProcess(editor.Command(v.draftPath), func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+func (v *AnnouncementView) request() (api.CreateAnnouncementRequest, error) {
+	req := api.CreateAnnouncementRequest{
+		Title: strings.TrimSpace(v.titleInput.Value()),
+	}
+	if req.Title == "" {
+		return req, fmt.Errorf("title is required")
+	}
+	if strings.TrimSpace(v.body) == "" {
+		return req, fmt.Errorf("body is empty, press Ctrl+E to write it")
+	}
+
+	if value := strings.TrimSpace(v.postAtInput.Value()); value != "" {
+		postAt, err := parseDateTime(value, 0)
+		if err != nil {
+			return req, err
+		}
+		if postAt.Before(time.Now()) {
+			return req, fmt.Errorf("post time %s is in the past", value)
+		}
+		req.DelayedPostAt = &postAt
+	}
+
+	message, err := markup.ToHTML(v.body)
+	if err != nil {
+		return req, err
+	}
+	req.Message = message
+	return req, nil
+}
+
+func (v *AnnouncementView) createAnnouncement(req api.CreateAnnouncementRequest) tea.Cmd {
+	return func() tea.Msg {
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+		if err := client.CreateAnnouncement(req); err != nil {
+			return errMsg(err)
+		}
+		if req.DelayedPostAt != nil {
+			return announcementCreatedMsg(fmt.Sprintf("Scheduled %q for %s", req.Title, req.DelayedPostAt.Format(dateTimeLayout)))
+		}
+		return announcementCreatedMsg(fmt.Sprintf("Posted %q", req.Title))
+	}
+}
+
+// Message types
+type editorFinishedMsg struct {
+	err error
+}
+
+type announcementCreatedMsg string
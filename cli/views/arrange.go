@@ -0,0 +1,149 @@
+package views
+
+import (
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+)
+
+// maxIndent is the deepest Canvas lets a module item be indented.
+const maxIndent = 5
+
+// arrangement is a module's items in order while they're being rearranged.
+// The server builds lessons the same way: an item at indent 0 starts a
+// lesson and the indented items after it are its children.
+type arrangement struct {
+	items []api.Lesson
+	moves []arrangedMove
+}
+
+// arrangedMove is an item on its way to another module.
+type arrangedMove struct {
+	item   api.Lesson
+	module api.Module
+}
+
+func newArrangement(lessons []api.ModuleNode) arrangement {
+	var a arrangement
+	for _, node := range lessons {
+		lesson := node.Lesson
+		lesson.Indent = 0
+		a.items = append(a.items, lesson)
+		for _, child := range node.Children {
+			child.Indent = max(child.Indent, 1)
+			a.items = append(a.items, child)
+		}
+	}
+	return a
+}
+
+func (a *arrangement) moveUp(i int) int {
+	if i <= 0 || i >= len(a.items) {
+		return i
+	}
+	a.items[i-1], a.items[i] = a.items[i], a.items[i-1]
+	return i - 1
+}
+
+func (a *arrangement) moveDown(i int) int {
+	if i < 0 || i >= len(a.items)-1 {
+		return i
+	}
+	a.items[i+1], a.items[i] = a.items[i], a.items[i+1]
+	return i + 1
+}
+
+func (a *arrangement) indent(i int) {
+	if i >= 0 && i < len(a.items) && a.items[i].Indent < maxIndent {
+		a.items[i].Indent++
+	}
+}
+
+func (a *arrangement) outdent(i int) {
+	if i >= 0 && i < len(a.items) && a.items[i].Indent > 0 {
+		a.items[i].Indent--
+	}
+}
+
+func (a *arrangement) moveTo(i int, module api.Module) {
+	if i < 0 || i >= len(a.items) {
+		return
+	}
+	a.moves = append(a.moves, arrangedMove{item: a.items[i], module: module})
+	a.items = append(a.items[:i:i], a.items[i+1:]...)
+}
+
+// lessons groups the items into the lessons the server will build.
+func (a arrangement) lessons() []api.ModuleNode {
+	var lessons []api.ModuleNode
+	for _, item := range a.items {
+		if item.Indent == 0 {
+			lessons = append(lessons, api.ModuleNode{Lesson: item, Children: []api.Lesson{}})
+			continue
+		}
+		if len(lessons) > 0 {
+			last := &lessons[len(lessons)-1]
+			last.Children = append(last.Children, item)
+		}
+	}
+	return lessons
+}
+
+// orphans counts the items before the first lesson, which no lesson shows.
+func (a arrangement) orphans() int {
+	for i, item := range a.items {
+		if item.Indent == 0 {
+			return i
+		}
+	}
+	return len(a.items)
+}
+
+// lessonOf is the index of the lesson item i ends up in, or -1.
+func (a arrangement) lessonOf(i int) int {
+	lesson := -1
+	for j := 0; j <= i && j < len(a.items); j++ {
+		if a.items[j].Indent == 0 {
+			lesson++
+		}
+	}
+	if i < a.orphans() {
+		return -1
+	}
+	return lesson
+}
+
+// changed reports whether the items differ from the original arrangement.
+func (a arrangement) changed(original arrangement) bool {
+	if len(a.moves) > 0 || len(a.items) != len(original.items) {
+		return true
+	}
+	for i := range a.items {
+		if a.items[i].ID != original.items[i].ID || a.items[i].Indent != original.items[i].Indent {
+			return true
+		}
+	}
+	return false
+}
+
+// itemChanged reports whether item i isn't where it started or as indented.
+func (a arrangement) itemChanged(i int, original arrangement) bool {
+	for j, item := range original.items {
+		if item.ID == a.items[i].ID {
+			return j != i || item.Indent != a.items[i].Indent
+		}
+	}
+	return true
+}
+
+func (a arrangement) request() api.UpdateModuleLayoutRequest {
+	req := api.UpdateModuleLayoutRequest{
+		Items: make([]api.ModuleItemLayout, 0, len(a.items)),
+		Moves: make([]api.ModuleItemMove, 0, len(a.moves)),
+	}
+	for _, item := range a.items {
+		req.Items = append(req.Items, api.ModuleItemLayout{ID: item.ID, Indent: item.Indent})
+	}
+	for _, move := range a.moves {
+		req.Moves = append(req.Moves, api.ModuleItemMove{ID: move.item.ID, ModuleID: move.module.ID})
+	}
+	return req
+}
@@ -0,0 +1,358 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/editor"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/markup"
+)
+
+const (
+	diffContext  = 2
+	diffPageSize = 30
+)
+
+var (
+	diffInsertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	diffDeleteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+type contentMode int
+
+const (
+	contentModePick contentMode = iota
+	contentModeLoading
+	contentModeEditing
+	contentModeDiff
+	contentModeSaving
+	contentModeSaved
+)
+
+// ContentEditView edits a page body or assignment description from a lesson
+// as Markdown in $EDITOR, then shows a diff before pushing it back as HTML.
+type ContentEditView struct {
+	lesson   api.ModuleNode
+	module   api.Module
+	items    []api.Lesson
+	selected int
+	mode     contentMode
+	item     api.Lesson
+	path     string
+	original string
+	edited   string
+	diff     []string
+	offset   int
+	err      error
+}
+
+func NewContentEditView(lesson api.ModuleNode, module api.Module) *ContentEditView {
+	log := logger.With(
+		"component", "content_edit_view",
+		"lesson_id", lesson.Lesson.ID,
+		"lesson_title", lesson.Lesson.Title,
+	)
+	log.Info("Creating new content edit view")
+
+	return &ContentEditView{
+		lesson: lesson,
+		module: module,
+		items:  EditableItems(lesson),
+	}
+}
+
+func (v *ContentEditView) Init() tea.Cmd {
+	if len(v.items) == 1 {
+		return v.load(v.items[0])
+	}
+	return nil
+}
+
+func (v *ContentEditView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With(
+		"component", "content_edit_view",
+		"lesson_id", v.lesson.Lesson.ID,
+		"lesson_title", v.lesson.Lesson.Title,
+	)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch v.mode {
+		case contentModePick:
+			switch msg.String() {
+			case "up", "k":
+				if v.selected > 0 {
+					v.selected--
+				}
+			case "down", "j":
+				if v.selected < len(v.items)-1 {
+					v.selected++
+				}
+			case "enter":
+				if len(v.items) > 0 {
+					return v, v.load(v.items[v.selected])
+				}
+			case "esc":
+				return v, v.back
+			}
+		case contentModeDiff:
+			switch msg.String() {
+			case "up", "k":
+				if v.offset > 0 {
+					v.offset--
+				}
+			case "down", "j":
+				if v.offset < len(v.diff)-diffPageSize {
+					v.offset++
+				}
+			case "e":
+				v.mode = contentModeEditing
+				return v, v.openEditor()
+			case "y", "ctrl+s":
+				// Converting back to HTML drops attributes Markdown can't
+				// hold, so an unchanged item is never written back
+				if len(v.diff) == 0 {
+					return v, nil
+				}
+				log.Info("Saving content", "item_id", v.item.ID, "item_type", v.item.Type)
+				v.mode = contentModeSaving
+				return v, v.save
+			case "n", "esc":
+				log.Info("Discarded content changes", "item_id", v.item.ID)
+				os.Remove(v.path)
+				return v, v.back
+			}
+		case contentModeSaved:
+			if msg.String() == "esc" || msg.String() == "enter" {
+				return v, v.back
+			}
+		default:
+			if msg.String() == "esc" && v.err != nil {
+				return v, v.back
+			}
+		}
+	case contentLoadedMsg:
+		log.Info("Loaded content", "item_id", msg.item.ID, "item_type", msg.item.Type)
+		v.item = msg.item
+		v.original = msg.markdown
+		v.path = msg.path
+		v.mode = contentModeEditing
+		return v, v.openEditor()
+	case editorFinishedMsg:
+		if msg.err != nil {
+			log.Error("Editor exited with error", "error", msg.err)
+			v.err = msg.err
+			return v, nil
+		}
+		edited, err := os.ReadFile(v.path)
+		if err != nil {
+			v.err = fmt.Errorf("failed to read edited content: %w", err)
+			return v, nil
+		}
+		v.edited = string(edited)
+		v.diff = formatDiff(markup.Diff(v.original, v.edited))
+		v.offset = 0
+		v.mode = contentModeDiff
+	case contentSavedMsg:
+		log.Info("Saved content", "item_id", v.item.ID)
+		os.Remove(v.path)
+		v.mode = contentModeSaved
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *ContentEditView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back.", v.err)
+	}
+
+	switch v.mode {
+	case contentModeLoading:
+		return fmt.Sprintf("Loading %s...", v.item.Title)
+	case contentModeEditing:
+		return fmt.Sprintf("Editing %s in $EDITOR...", v.item.Title)
+	case contentModeSaving:
+		return fmt.Sprintf("Saving %s...", v.item.Title)
+	case contentModeSaved:
+		return fmt.Sprintf("Saved %s.\n\nPress enter to go back.", v.item.Title)
+	case contentModeDiff:
+		s := fmt.Sprintf("Changes to %s (%s)\n\n", v.item.Title, v.item.Type)
+		if len(v.diff) == 0 {
+			s += "No changes.\n\n"
+			return s + "e (edit again), Esc (back)"
+		}
+		end := min(v.offset+diffPageSize, len(v.diff))
+		s += strings.Join(v.diff[v.offset:end], "\n") + "\n\n"
+		if len(v.diff) > diffPageSize {
+			s += fmt.Sprintf("Lines %d-%d of %d\n", v.offset+1, end, len(v.diff))
+		}
+		return s + "y (save to Canvas), e (edit again), n/Esc (discard), ↑/↓ (scroll)"
+	}
+
+	if len(v.items) == 0 {
+		return fmt.Sprintf("%s has no pages or assignments to edit.\n\nPress esc to go back.", v.lesson.Lesson.Title)
+	}
+
+	s := fmt.Sprintf("Edit content: %s\n\n", v.lesson.Lesson.Title)
+	s += "Select an item:\n\n"
+	for i, item := range v.items {
+		cursor := " "
+		if v.selected == i {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %s (%s)\n", cursor, item.Title, item.Type)
+	}
+	s += "\nPress enter to edit, esc to go back."
+	return s
+}
+
+func (v *ContentEditView) back() tea.Msg {
+	return LessonSelectedMsg{Lesson: v.lesson, Module: v.module}
+}
+
+func (v *ContentEditView) load(item api.Lesson) tea.Cmd {
+	v.item = item
+	v.mode = contentModeLoading
+	return func() tea.Msg {
+		body, err := FetchLessonContent(item)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		markdown, err := markup.ToMarkdown(body)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		path, err := editor.WriteTemp("canvas-content-*.md", markdown)
+		if err != nil {
+			return errMsg(err)
+		}
+		return contentLoadedMsg{item: item, markdown: markdown, path: path}
+	}
+}
+
+func (v *ContentEditView) openEditor() tea.Cmd {
+	return tea.ExecProcess(editor.Command(v.path), func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+func (v *ContentEditView) save() tea.Msg {
+	body, err := markup.ToHTMLWithRaw(v.edited)
+	if err != nil {
+		return errMsg(err)
+	}
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	switch v.item.Type {
+	case "Page":
+		err = client.UpdatePage(v.item.PageURL, api.UpdatePageRequest{Body: body})
+	case "Assignment":
+		err = client.UpdateAssignment(v.item.ContentID, api.UpdateAssignmentRequest{Description: &body})
+	default:
+		err = fmt.Errorf("can't edit %s items", v.item.Type)
+	}
+	if err != nil {
+		return errMsg(err)
+	}
+	return contentSavedMsg{}
+}
+
+// EditableItems returns the lesson's own item and children that have
+// editable HTML content.
+func EditableItems(lesson api.ModuleNode) []api.Lesson {
+	var items []api.Lesson
+	for _, item := range append([]api.Lesson{lesson.Lesson}, lesson.Children...) {
+		if (item.Type == "Page" && item.PageURL != "") || (item.Type == "Assignment" && item.ContentID != 0) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// FetchLessonContent returns the HTML body of a Page or the description of an
+// Assignment.
+func FetchLessonContent(item api.Lesson) (string, error) {
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	switch item.Type {
+	case "Page":
+		page, err := client.GetPage(item.PageURL)
+		if err != nil {
+			return "", err
+		}
+		return page.Body, nil
+	case "Assignment":
+		assignment, err := client.GetAssignment(item.ContentID)
+		if err != nil {
+			return "", err
+		}
+		return assignment.Description, nil
+	}
+	return "", fmt.Errorf("%s items have no content", item.Type)
+}
+
+// formatDiff renders the changed lines with a little surrounding context.
+func formatDiff(lines []markup.DiffLine) []string {
+	if !markup.Changed(lines) {
+		return nil
+	}
+
+	show := make([]bool, len(lines))
+	for i, line := range lines {
+		if line.Op == markup.DiffEqual {
+			continue
+		}
+		for j := max(0, i-diffContext); j <= min(len(lines)-1, i+diffContext); j++ {
+			show[j] = true
+		}
+	}
+
+	var out []string
+	for i, line := range lines {
+		if !show[i] {
+			if i > 0 && show[i-1] {
+				out = append(out, "  …")
+			}
+			continue
+		}
+		text := fmt.Sprintf("%c %s", line.Op, line.Text)
+		switch line.Op {
+		case markup.DiffInsert:
+			text = diffInsertStyle.Render(text)
+		case markup.DiffDelete:
+			text = diffDeleteStyle.Render(text)
+		}
+		out = append(out, text)
+	}
+	return out
+}
+
+// Message types
+type contentLoadedMsg struct {
+	item     api.Lesson
+	markdown string
+	path     string
+}
+
+type contentSavedMsg struct{}
+
+type EditContentMsg struct {
+	Lesson api.ModuleNode
+	Module api.Module
+}
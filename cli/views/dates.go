@@ -0,0 +1,22 @@
+package views
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateTimeLayout is how the views read and show local times.
+const dateTimeLayout = "2006-01-02 15:04"
+
+// parseDateTime reads a local "YYYY-MM-DD HH:MM", or a date alone at dayTime
+// past midnight, e.g. 23h59m for the end of the day.
+func parseDateTime(value string, dayTime time.Duration) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateTimeLayout, value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD [HH:MM]", value)
+	}
+	return t.Add(dayTime), nil
+}
@@ -5,16 +5,33 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/export"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/grades"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
 )
 
+const (
+	SortByScore = "score"
+	SortByName  = "name"
+)
+
+var enrollmentStateFilters = []string{"", "active", "invited", "inactive", "completed"}
+
 type EnrollmentsView struct {
 	enrollments []api.Enrollment
 	selected    int
 	err         error
+	sortBy      string
+	stateFilter int
+	atRiskOnly  bool
+	// For export
+	exportMode     bool
+	exportSelected int
+	status         string
 }
 
 func NewEnrollmentView() *EnrollmentsView {
@@ -23,6 +40,7 @@ func NewEnrollmentView() *EnrollmentsView {
 	return &EnrollmentsView{
 		enrollments: []api.Enrollment{},
 		selected:    0,
+		sortBy:      SortByScore,
 	}
 }
 
@@ -35,6 +53,29 @@ func (v *EnrollmentsView) Init() tea.Cmd {
 func (v *EnrollmentsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log := logger.With("component", "enrollments_view")
 
+	if v.exportMode {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "up", "k":
+				if v.exportSelected > 0 {
+					v.exportSelected--
+				}
+			case "down", "j":
+				if v.exportSelected < len(export.Formats)-1 {
+					v.exportSelected++
+				}
+			case "enter":
+				v.exportMode = false
+				return v, v.exportEnrollments(export.Formats[v.exportSelected])
+			case "esc":
+				v.exportMode = false
+			}
+		}
+		return v, nil
+	}
+
+	visible := v.visible()
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -43,12 +84,40 @@ func (v *EnrollmentsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				v.selected--
 			}
 		case "down", "j":
-			if v.selected < len(v.enrollments)-1 {
+			if v.selected < len(visible)-1 {
 				v.selected++
 			}
+		case "s":
+			if v.sortBy == SortByScore {
+				v.sortBy = SortByName
+			} else {
+				v.sortBy = SortByScore
+			}
+			v.selected = 0
+		case "f":
+			v.stateFilter = (v.stateFilter + 1) % len(enrollmentStateFilters)
+			v.selected = 0
+		case "r":
+			v.atRiskOnly = !v.atRiskOnly
+			v.selected = 0
+		case "m":
+			if len(visible) > 0 {
+				log.Info("Messaging students", "count", len(visible))
+				return v, func() tea.Msg {
+					return ComposeMessageMsg{
+						Recipients: visible,
+						Back:       EnrollmentsView{},
+					}
+				}
+			}
+		case "e":
+			if len(visible) > 0 {
+				v.exportMode = true
+				v.status = ""
+			}
 		case "enter":
-			if len(v.enrollments) > 0 {
-				selectedEnrollment := v.enrollments[v.selected]
+			if len(visible) > 0 {
+				selectedEnrollment := visible[v.selected]
 				log.Info("Selected enrollment",
 					"student_name", selectedEnrollment.User.Name,
 					"enrollment_type", selectedEnrollment.Type)
@@ -69,6 +138,9 @@ func (v *EnrollmentsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case enrollmentsMsg:
 		log.Info("Received enrollments", "count", len(msg))
 		v.enrollments = msg
+	case exportDoneMsg:
+		log.Info("Exported enrollments", "path", msg.path, "count", msg.count)
+		v.status = fmt.Sprintf("Exported %d students to %s", msg.count, msg.path)
 	case errMsg:
 		log.Error("Error occurred", "error", msg)
 		v.err = msg
@@ -78,8 +150,6 @@ func (v *EnrollmentsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (v *EnrollmentsView) View() string {
-	var passingScore = 70.0
-
 	if v.err != nil {
 		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
 	}
@@ -88,18 +158,37 @@ func (v *EnrollmentsView) View() string {
 		return "Loading enrollments...\n\nPress esc to go back, q to quit."
 	}
 
-	s := fmt.Sprintf("Course Enrollments (%d students)\n\n", len(v.enrollments))
+	if v.exportMode {
+		s := fmt.Sprintf("Export %d students as:\n\n", len(v.exported()))
+		for i, format := range export.Formats {
+			cursor := " "
+			if v.exportSelected == i {
+				cursor = ">"
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, format.Label())
+		}
+		s += "\nPress enter to export, esc to cancel"
+		return s
+	}
+
+	visible := v.visible()
+	filter := enrollmentStateFilters[v.stateFilter]
+	if filter == "" {
+		filter = "all"
+	}
+
+	if v.atRiskOnly {
+		filter += ", at risk"
+	}
+
+	s := fmt.Sprintf("Course Enrollments (%d students)\n", len(visible))
+	s += fmt.Sprintf("Sort: %s  Filter: %s\n\n", v.sortBy, filter)
 
 	// Create table header
 	s += v.formatTableHeader()
 	s += v.formatTableSeparator()
 
-	// Add enrollment rows
-	sort.Slice(v.enrollments, func(i, j int) bool {
-		return v.enrollments[i].Grades.Score < v.enrollments[j].Grades.Score
-	})
-
-	failing, passing := partition(v.enrollments, func(e api.Enrollment) float64 { return float64(e.Grades.Score) }, passingScore)
+	failing, passing := partition(visible, func(e api.Enrollment) float64 { return float64(e.Grades.Score) }, grades.PassingScore)
 
 	// loop failing
 	for i, enrollment := range failing {
@@ -111,7 +200,9 @@ func (v *EnrollmentsView) View() string {
 	}
 
 	// display partition
-	s += v.formatPartition()
+	if !v.atRiskOnly {
+		s += v.formatPartition()
+	}
 
 	// loop passing
 	offset := len(failing)
@@ -124,11 +215,95 @@ func (v *EnrollmentsView) View() string {
 	}
 
 	s += "\n" + v.formatTableSeparator()
-	s += "\nNavigation: ↑/k (up), ↓/j (down), Enter (select), Esc (back), q (quit)"
+	if v.status != "" {
+		s += "\n" + v.status + "\n"
+	}
+	s += "\nNavigation: ↑/k (up), ↓/j (down), Enter (select), s (sort), f (filter), r (at risk), m (message), e (export), Esc (back), q (quit)"
 
 	return s
 }
 
+// visible returns the enrollments in the order they are displayed: filtered
+// by state, sorted, then split into failing and passing.
+func (v *EnrollmentsView) visible() []api.Enrollment {
+	ordered := OrderEnrollments(v.enrollments, enrollmentStateFilters[v.stateFilter], v.sortBy)
+	failing, passing := partition(ordered, func(e api.Enrollment) float64 { return float64(e.Grades.Score) }, grades.PassingScore)
+	if v.atRiskOnly {
+		return failing
+	}
+	return append(failing, passing...)
+}
+
+// exported returns the enrollments to export in the order `enrollments
+// export` writes them: filtered and sorted, without the failing/passing
+// split the table shows.
+func (v *EnrollmentsView) exported() []api.Enrollment {
+	ordered := OrderEnrollments(v.enrollments, enrollmentStateFilters[v.stateFilter], v.sortBy)
+	if !v.atRiskOnly {
+		return ordered
+	}
+	atRisk, _ := partition(ordered, func(e api.Enrollment) float64 { return float64(e.Grades.Score) }, grades.PassingScore)
+	return atRisk
+}
+
+func (v *EnrollmentsView) exportEnrollments(format export.Format) tea.Cmd {
+	enrollments := v.exported()
+	return func() tea.Msg {
+		log := logger.With(
+			"component", "enrollments_view",
+			"action", "export_enrollments",
+			"format", format,
+		)
+
+		path := fmt.Sprintf("roster-%s.%s", time.Now().Format("2006-01-02"), format)
+		file, err := os.Create(path)
+		if err != nil {
+			log.Error("Failed to create export file", "error", err)
+			return errMsg(fmt.Errorf("failed to create %s: %w", path, err))
+		}
+		if err := export.WriteRoster(file, format, enrollments); err != nil {
+			file.Close()
+			log.Error("Failed to export enrollments", "error", err)
+			return errMsg(err)
+		}
+		if err := file.Close(); err != nil {
+			log.Error("Failed to close export file", "error", err)
+			return errMsg(fmt.Errorf("failed to write %s: %w", path, err))
+		}
+		return exportDoneMsg{path: path, count: len(enrollments)}
+	}
+}
+
+// OrderEnrollments returns the enrollments matching state (all when empty),
+// sorted by SortByScore or SortByName.
+func OrderEnrollments(enrollments []api.Enrollment, state string, sortBy string) []api.Enrollment {
+	var ordered []api.Enrollment
+	for _, enrollment := range enrollments {
+		if state == "" || enrollment.State == state {
+			ordered = append(ordered, enrollment)
+		}
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if sortBy == SortByName {
+			return ordered[i].User.Name < ordered[j].User.Name
+		}
+		return ordered[i].Grades.Score < ordered[j].Grades.Score
+	})
+	return ordered
+}
+
+// StudentEnrollments drops teacher, TA and observer enrollments.
+func StudentEnrollments(enrollments []api.Enrollment) []api.Enrollment {
+	var students []api.Enrollment
+	for _, enrollment := range enrollments {
+		if enrollment.Type == "StudentEnrollment" {
+			students = append(students, enrollment)
+		}
+	}
+	return students
+}
+
 func (v *EnrollmentsView) formatTableHeader() string {
 	return fmt.Sprintf("  %-30s %-15s %-12s %-12s %-10s\n",
 		"Student Name", "Type", "State", "Current Grade", "Final Grade")
@@ -191,12 +366,7 @@ func (v *EnrollmentsView) fetchEnrollments() tea.Msg {
 	}
 
 	// Filter to only student enrollments if needed
-	var studentEnrollments []api.Enrollment
-	for _, enrollment := range enrollments {
-		if enrollment.Type == "StudentEnrollment" {
-			studentEnrollments = append(studentEnrollments, enrollment)
-		}
-	}
+	studentEnrollments := StudentEnrollments(enrollments)
 
 	log.Info("Successfully fetched enrollments", "total_count", len(enrollments), "student_count", len(studentEnrollments))
 	return enrollmentsMsg(studentEnrollments)
@@ -217,6 +387,11 @@ func partition[T any](slice []T, getValue func(T) float64, threshold float64) ([
 // Message types
 type enrollmentsMsg []api.Enrollment
 
+type exportDoneMsg struct {
+	path  string
+	count int
+}
+
 type EnrollmentSelectedMsg struct {
 	Enrollment api.Enrollment
 }
@@ -0,0 +1,435 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const extendPageSize = 15
+
+type extendMode int
+
+const (
+	extendModeSelect extendMode = iota
+	extendModeInput
+	extendModeConfirm
+	extendModeSaving
+	extendModeDone
+)
+
+type extension struct {
+	assignment api.Assignment
+	dueAt      time.Time
+	lockAt     *time.Time
+	err        error
+}
+
+// ExtendDeadlineView moves due dates for one student by creating Canvas
+// assignment overrides on the assignments picked.
+type ExtendDeadlineView struct {
+	enrollment  api.Enrollment
+	assignments []api.Assignment
+	existing    map[int]api.AssignmentOverride
+	checked     map[int]bool
+	selected    int
+	input       textinput.Model
+	mode        extendMode
+	plan        []extension
+	loaded      bool
+	err         error
+}
+
+type ExtendDeadlineMsg struct {
+	Enrollment api.Enrollment
+}
+
+func NewExtendDeadlineView(enrollment api.Enrollment) *ExtendDeadlineView {
+	log := logger.With("component", "extend_deadline_view", "student_name", enrollment.User.Name)
+	log.Info("Creating new extend deadline view")
+
+	ti := textinput.New()
+	ti.Placeholder = "+2d or YYYY-MM-DD [HH:MM]"
+	ti.CharLimit = 16
+	ti.Width = 30
+
+	return &ExtendDeadlineView{
+		enrollment: enrollment,
+		checked:    map[int]bool{},
+		input:      ti,
+	}
+}
+
+func (v *ExtendDeadlineView) Init() tea.Cmd {
+	log := logger.With("component", "extend_deadline_view", "student_name", v.enrollment.User.Name)
+	log.Info("Initializing extend deadline view")
+	return v.fetchAssignments
+}
+
+func (v *ExtendDeadlineView) CapturingInput() bool {
+	return v.mode == extendModeInput
+}
+
+func (v *ExtendDeadlineView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "extend_deadline_view", "student_name", v.enrollment.User.Name)
+
+	switch v.mode {
+	case extendModeInput:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "enter":
+				v.plan = v.planExtensions(strings.TrimSpace(v.input.Value()), time.Now())
+				v.mode = extendModeConfirm
+				return v, nil
+			case "esc":
+				v.mode = extendModeSelect
+				return v, nil
+			}
+		}
+		var cmd tea.Cmd
+		v.input, cmd = v.input.Update(msg)
+		return v, cmd
+	case extendModeConfirm:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "y":
+				if planErrors(v.plan) > 0 {
+					return v, nil
+				}
+				log.Info("Creating extensions", "count", len(v.plan))
+				v.mode = extendModeSaving
+				return v, v.createOverrides(v.plan)
+			case "n", "esc":
+				v.mode = extendModeInput
+				v.input.Focus()
+				return v, textinput.Blink
+			}
+		}
+		return v, nil
+	case extendModeSaving:
+		if msg, ok := msg.(extensionsCreatedMsg); ok {
+			log.Info("Created extensions", "count", len(msg), "failed", planErrors(msg))
+			v.plan = msg
+			v.mode = extendModeDone
+		}
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.assignments)-1 {
+				v.selected++
+			}
+		case " ", "x":
+			if v.mode == extendModeSelect && len(v.assignments) > 0 {
+				id := v.assignments[v.selected].ID
+				v.checked[id] = !v.checked[id]
+			}
+		case "enter", "e":
+			if v.mode == extendModeSelect && v.checkedCount() > 0 {
+				v.mode = extendModeInput
+				v.input.SetValue("")
+				v.input.Focus()
+				return v, textinput.Blink
+			}
+		case "esc":
+			if v.mode == extendModeDone {
+				v.mode = extendModeSelect
+				v.checked = map[int]bool{}
+				return v, v.fetchAssignments
+			}
+			log.Info("Returning to student view")
+			enrollment := v.enrollment
+			return v, func() tea.Msg {
+				return EnrollmentSelectedMsg{Enrollment: enrollment}
+			}
+		}
+	case extendAssignmentsMsg:
+		log.Info("Received assignments", "count", len(msg.assignments), "overrides", len(msg.existing))
+		v.assignments = msg.assignments
+		v.existing = msg.existing
+		v.selected = min(v.selected, max(len(v.assignments)-1, 0))
+		v.loaded = true
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *ExtendDeadlineView) checkedCount() int {
+	count := 0
+	for _, checked := range v.checked {
+		if checked {
+			count++
+		}
+	}
+	return count
+}
+
+// studentDueAt is the due date the student has now, counting an existing
+// extension.
+func (v *ExtendDeadlineView) studentDueAt(a api.Assignment) *time.Time {
+	if override, ok := v.existing[a.ID]; ok && override.DueAt != nil {
+		return override.DueAt
+	}
+	return a.DueAt
+}
+
+// planExtensions works out the new due date for each checked assignment.
+// The lock date moves with it when it would otherwise fall before the new
+// due date. A student who shares an override with others can't be given
+// their own, since Canvas allows one override per student per assignment.
+func (v *ExtendDeadlineView) planExtensions(spec string, now time.Time) []extension {
+	var plan []extension
+	for _, a := range v.assignments {
+		if !v.checked[a.ID] {
+			continue
+		}
+		ext := extension{assignment: a}
+		if override, ok := v.existing[a.ID]; ok && len(override.StudentIDs) > 1 {
+			ext.err = fmt.Errorf("shares %q with %d others, remove them from it in Canvas first", override.Title, len(override.StudentIDs)-1)
+			plan = append(plan, ext)
+			continue
+		}
+		ext.dueAt, ext.err = parseExtension(spec, v.studentDueAt(a), now)
+		if ext.err == nil && a.LockAt != nil && a.LockAt.Before(ext.dueAt) {
+			lockAt := ext.dueAt
+			ext.lockAt = &lockAt
+		}
+		plan = append(plan, ext)
+	}
+	return plan
+}
+
+var relativeExtension = regexp.MustCompile(`^\+(\d+)([dh])$`)
+
+// parseExtension reads "+Nd" or "+Nh" relative to the current due date, or
+// an absolute local date with an optional time. Dates without a time are
+// due at 23:59.
+func parseExtension(spec string, dueAt *time.Time, now time.Time) (time.Time, error) {
+	if match := relativeExtension.FindStringSubmatch(spec); match != nil {
+		if dueAt == nil {
+			return time.Time{}, fmt.Errorf("no due date to extend, use an absolute date")
+		}
+		n, _ := strconv.Atoi(match[1])
+		unit := 24 * time.Hour
+		if match[2] == "h" {
+			unit = time.Hour
+		}
+		return dueAt.Add(time.Duration(n) * unit), nil
+	}
+
+	t, err := parseDateTime(spec, 23*time.Hour+59*time.Minute)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use +2d, +12h or YYYY-MM-DD [HH:MM]", spec)
+	}
+	if t.Before(now) {
+		return time.Time{}, fmt.Errorf("%s is in the past", spec)
+	}
+	return t, nil
+}
+
+func planErrors(plan []extension) int {
+	count := 0
+	for _, ext := range plan {
+		if ext.err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func (v *ExtendDeadlineView) View() string {
+	title := fmt.Sprintf("Extend Deadline: %s", v.enrollment.User.Name)
+	s := title + "\n" + strings.Repeat("=", len(title)) + "\n\n"
+
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+	if !v.loaded {
+		return s + "Loading assignments..."
+	}
+
+	switch v.mode {
+	case extendModeConfirm, extendModeSaving, extendModeDone:
+		return s + v.planView()
+	}
+
+	if len(v.assignments) == 0 {
+		return s + "No published assignments.\n\nPress esc to go back, q to quit."
+	}
+
+	s += fmt.Sprintf("    %-35s %-18s %s\n", "Assignment", "Due", "Extended to")
+	start, end := pageBounds(v.selected, len(v.assignments), extendPageSize)
+	for i := start; i < end; i++ {
+		a := v.assignments[i]
+		cursor := " "
+		if i == v.selected {
+			cursor = ">"
+		}
+		check := "[ ]"
+		if v.checked[a.ID] {
+			check = "[x]"
+		}
+		extended := ""
+		if override, ok := v.existing[a.ID]; ok {
+			extended = formatDue(override.DueAt)
+		}
+		s += fmt.Sprintf("%s %s %-35s %-18s %s\n", cursor, check, truncate(a.Name, 35), formatDue(a.DueAt), extended)
+	}
+	s += fmt.Sprintf("\n  %d-%d of %d, %d selected\n\n", start+1, end, len(v.assignments), v.checkedCount())
+
+	if v.mode == extendModeInput {
+		s += fmt.Sprintf("New due date for %d assignments:\n%s\n\n", v.checkedCount(), v.input.View())
+		s += "+2d or +12h moves each current due date, YYYY-MM-DD [HH:MM] sets one date.\n"
+		s += "Press enter to preview, esc to cancel"
+		return s
+	}
+
+	s += "Keys: ↑/k ↓/j move, space/x select, enter/e set new due date\n"
+	s += "Press esc to go back, q to quit."
+	return s
+}
+
+func (v *ExtendDeadlineView) planView() string {
+	var s string
+	for _, ext := range v.plan {
+		from := formatDue(v.studentDueAt(ext.assignment))
+		switch {
+		case ext.err != nil && v.mode == extendModeDone:
+			s += fmt.Sprintf("  ✗ %-35s %v\n", truncate(ext.assignment.Name, 35), ext.err)
+		case ext.err != nil:
+			s += fmt.Sprintf("  ! %-35s %v\n", truncate(ext.assignment.Name, 35), ext.err)
+		default:
+			mark := " "
+			if v.mode == extendModeDone {
+				mark = "✓"
+			}
+			lock := ""
+			if ext.lockAt != nil {
+				lock = "  (lock date moved too)"
+			}
+			s += fmt.Sprintf("  %s %-35s %s → %s%s\n", mark, truncate(ext.assignment.Name, 35), from, formatDue(&ext.dueAt), lock)
+		}
+	}
+	s += "\n"
+
+	switch v.mode {
+	case extendModeConfirm:
+		if failed := planErrors(v.plan); failed > 0 {
+			s += fmt.Sprintf("%d assignments can't be extended that way. Press n to change the date.", failed)
+		} else {
+			s += fmt.Sprintf("Create %d extensions for %s? (y/n)", len(v.plan), v.enrollment.User.Name)
+		}
+	case extendModeSaving:
+		s += "Creating extensions..."
+	case extendModeDone:
+		if failed := planErrors(v.plan); failed > 0 {
+			s += fmt.Sprintf("%d of %d extensions failed.\n\n", failed, len(v.plan))
+		} else {
+			s += fmt.Sprintf("Created %d extensions.\n\n", len(v.plan))
+		}
+		s += "Press esc to go back, q to quit."
+	}
+	return s
+}
+
+func formatDue(t *time.Time) string {
+	if t == nil {
+		return "no due date"
+	}
+	return t.Local().Format("Mon Jan 2 15:04")
+}
+
+func (v *ExtendDeadlineView) fetchAssignments() tea.Msg {
+	log := logger.With(
+		"component", "extend_deadline_view",
+		"action", "fetch_assignments",
+		"student_name", v.enrollment.User.Name,
+	)
+	log.Info("Fetching assignments and overrides")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+	assignments, err := client.GetAssignments()
+	if err != nil {
+		log.Error("Failed to fetch assignments", "error", err)
+		return errMsg(err)
+	}
+	overrides, err := client.GetOverrides()
+	if err != nil {
+		log.Error("Failed to fetch overrides", "error", err)
+		return errMsg(err)
+	}
+
+	var published []api.Assignment
+	for _, a := range assignments {
+		if a.Published {
+			published = append(published, a)
+		}
+	}
+	existing := map[int]api.AssignmentOverride{}
+	for _, o := range overrides {
+		for _, id := range o.StudentIDs {
+			if id == v.enrollment.User.ID {
+				existing[o.AssignmentID] = o
+			}
+		}
+	}
+	return extendAssignmentsMsg{assignments: published, existing: existing}
+}
+
+func (v *ExtendDeadlineView) createOverrides(plan []extension) tea.Cmd {
+	enrollment := v.enrollment
+	return func() tea.Msg {
+		log := logger.With(
+			"component", "extend_deadline_view",
+			"action", "create_overrides",
+			"student_name", enrollment.User.Name,
+		)
+
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+		results := make([]extension, len(plan))
+		for i, ext := range plan {
+			_, ext.err = client.CreateOverride(ext.assignment.ID, api.CreateOverrideRequest{
+				StudentIDs: []int{enrollment.User.ID},
+				Title:      "Extension: " + enrollment.User.Name,
+				DueAt:      ext.dueAt,
+				LockAt:     ext.lockAt,
+			})
+			if ext.err != nil {
+				log.Error("Failed to create override", "error", ext.err, "assignment_id", ext.assignment.ID)
+			}
+			results[i] = ext
+		}
+		return extensionsCreatedMsg(results)
+	}
+}
+
+// Message types
+type extendAssignmentsMsg struct {
+	assignments []api.Assignment
+	existing    map[int]api.AssignmentOverride
+}
+
+type extensionsCreatedMsg []extension
@@ -0,0 +1,405 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/feedback"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+type feedbackMode int
+
+const (
+	feedbackModeList feedbackMode = iota
+	feedbackModeEdit
+	feedbackModeImport
+	feedbackModeExport
+	feedbackModeHistory
+)
+
+type FeedbackLibraryView struct {
+	path     string
+	library  *feedback.Library
+	selected int
+	mode     feedbackMode
+	status   string
+	err      error
+	// For editing a template
+	editing   string
+	nameInput textinput.Model
+	bodyInput textarea.Model
+	editField int
+	// For import and export
+	pathInput textinput.Model
+	// For browsing and restoring earlier versions
+	historySelected int
+}
+
+func NewFeedbackLibraryView() *FeedbackLibraryView {
+	log := logger.With("component", "feedback_library_view")
+	log.Info("Creating new feedback library view")
+
+	ni := textinput.New()
+	ni.Placeholder = "Template name"
+	ni.CharLimit = 60
+	ni.Width = 40
+
+	bi := textarea.New()
+	bi.Placeholder = "Hi {{first_name}}, ..."
+	bi.SetWidth(70)
+	bi.SetHeight(6)
+
+	pi := textinput.New()
+	pi.Placeholder = "feedback.yaml"
+	pi.Width = 60
+
+	path := feedback.DefaultPath()
+	library, err := feedback.Load(path)
+	if err != nil {
+		log.Error("Failed to load feedback library", "error", err, "path", path)
+	}
+
+	return &FeedbackLibraryView{
+		path:      path,
+		library:   library,
+		err:       err,
+		nameInput: ni,
+		bodyInput: bi,
+		pathInput: pi,
+	}
+}
+
+func (v *FeedbackLibraryView) Init() tea.Cmd {
+	return nil
+}
+
+func (v *FeedbackLibraryView) CapturingInput() bool {
+	return v.mode != feedbackModeList && v.mode != feedbackModeHistory
+}
+
+func (v *FeedbackLibraryView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "feedback_library_view")
+
+	if v.library == nil {
+		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		}
+		return v, nil
+	}
+
+	switch v.mode {
+	case feedbackModeEdit:
+		return v.updateEdit(msg)
+	case feedbackModeImport, feedbackModeExport:
+		return v.updatePath(msg)
+	case feedbackModeHistory:
+		return v.updateHistory(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.library.Templates)-1 {
+				v.selected++
+			}
+		case "a":
+			v.startEdit(feedback.Template{})
+			return v, textinput.Blink
+		case "e", "enter":
+			if len(v.library.Templates) > 0 {
+				v.startEdit(v.library.Templates[v.selected])
+				return v, textinput.Blink
+			}
+		case "d":
+			if len(v.library.Templates) > 0 {
+				name := v.library.Templates[v.selected].Name
+				log.Info("Removing template", "name", name)
+				v.library.Remove(name)
+				if v.selected > 0 && v.selected >= len(v.library.Templates) {
+					v.selected--
+				}
+				v.save(fmt.Sprintf("Removed %q", name))
+			}
+		case "h":
+			if len(v.library.Templates) > 0 {
+				v.mode = feedbackModeHistory
+				v.historySelected = 0
+				v.err = nil
+			}
+		case "i":
+			v.startPath(feedbackModeImport)
+			return v, textinput.Blink
+		case "x":
+			v.startPath(feedbackModeExport)
+			return v, textinput.Blink
+		case "esc":
+			log.Info("Returning to home view")
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func (v *FeedbackLibraryView) updateEdit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			v.mode = feedbackModeList
+			return v, nil
+		case "tab", "shift+tab":
+			v.editField = 1 - v.editField
+			if v.editField == 0 {
+				v.bodyInput.Blur()
+				v.nameInput.Focus()
+			} else {
+				v.nameInput.Blur()
+				v.bodyInput.Focus()
+			}
+			return v, nil
+		case "ctrl+s":
+			template := feedback.Template{
+				Name: strings.TrimSpace(v.nameInput.Value()),
+				Body: strings.TrimSpace(v.bodyInput.Value()),
+			}
+			if err := v.library.Put(v.editing, template); err != nil {
+				v.err = err
+				return v, nil
+			}
+			v.mode = feedbackModeList
+			v.save(fmt.Sprintf("Saved %q", template.Name))
+			return v, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if v.editField == 0 {
+		v.nameInput, cmd = v.nameInput.Update(msg)
+	} else {
+		v.bodyInput, cmd = v.bodyInput.Update(msg)
+	}
+	return v, cmd
+}
+
+func (v *FeedbackLibraryView) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "feedback_library_view")
+	template := v.library.Templates[v.selected]
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "k":
+			if v.historySelected > 0 {
+				v.historySelected--
+			}
+		case "down", "j":
+			if v.historySelected < len(template.History)-1 {
+				v.historySelected++
+			}
+		case "r", "enter":
+			if len(template.History) == 0 {
+				return v, nil
+			}
+			// History is listed newest first
+			revision := template.History[len(template.History)-1-v.historySelected]
+			log.Info("Restoring template version", "name", template.Name, "version", revision.Version)
+			if err := v.library.Restore(template.Name, revision.Version); err != nil {
+				v.err = err
+				return v, nil
+			}
+			v.mode = feedbackModeList
+			v.save(fmt.Sprintf("Restored version %d of %q as version %d", revision.Version, template.Name, v.library.Templates[v.selected].Version))
+		case "esc":
+			v.mode = feedbackModeList
+		}
+	}
+	return v, nil
+}
+
+func (v *FeedbackLibraryView) updatePath(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "feedback_library_view")
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			v.mode = feedbackModeList
+			return v, nil
+		case "enter":
+			path := strings.TrimSpace(v.pathInput.Value())
+			if path == "" {
+				v.err = fmt.Errorf("enter a file path")
+				return v, nil
+			}
+
+			mode := v.mode
+			v.mode = feedbackModeList
+			if mode == feedbackModeExport {
+				log.Info("Exporting feedback library", "path", path)
+				if err := v.library.Save(path); err != nil {
+					v.err = err
+					return v, nil
+				}
+				v.err = nil
+				v.status = fmt.Sprintf("Exported %d templates to %s", len(v.library.Templates), path)
+				return v, nil
+			}
+
+			log.Info("Importing feedback library", "path", path)
+			file, err := os.Open(path)
+			if err != nil {
+				v.err = fmt.Errorf("failed to open %s: %w", path, err)
+				return v, nil
+			}
+			defer file.Close()
+			imported, err := feedback.Decode(file)
+			if err != nil {
+				v.err = err
+				return v, nil
+			}
+			added, updated := v.library.Merge(imported)
+			v.save(fmt.Sprintf("Imported %s: %d added, %d updated", path, added, updated))
+			return v, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	v.pathInput, cmd = v.pathInput.Update(msg)
+	return v, cmd
+}
+
+func (v *FeedbackLibraryView) View() string {
+	if v.library == nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back.", v.err)
+	}
+
+	switch v.mode {
+	case feedbackModeEdit:
+		title := "New template"
+		if v.editing != "" {
+			title = fmt.Sprintf("Edit template %q", v.editing)
+		}
+		s := title + "\n\n"
+		s += "Name:\n" + v.nameInput.View() + "\n\n"
+		s += "Body:\n" + v.bodyInput.View() + "\n\n"
+		s += "Placeholders: " + strings.Join(feedback.Placeholders, ", ") + "\n\n"
+		if v.err != nil {
+			s += fmt.Sprintf("Error: %v\n\n", v.err)
+		}
+		s += "Tab (next field), Ctrl+S (save), Esc (cancel)"
+		return s
+	case feedbackModeImport, feedbackModeExport:
+		action := "Import templates from"
+		if v.mode == feedbackModeExport {
+			action = "Export templates to"
+		}
+		return fmt.Sprintf("%s YAML file:\n%s\n\nPress enter to confirm, esc to cancel", action, v.pathInput.View())
+	}
+
+	if v.mode == feedbackModeHistory {
+		return v.historyView()
+	}
+
+	s := fmt.Sprintf("Feedback Library (%d templates)\n", len(v.library.Templates))
+	s += fmt.Sprintf("%s\n\n", v.path)
+
+	if len(v.library.Templates) == 0 {
+		s += "No templates yet, press a to add one.\n"
+	}
+	for i, t := range v.library.Templates {
+		cursor := " "
+		if v.selected == i {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %-24s v%-3d %s\n", cursor, truncate(t.Name, 24), t.Version, truncate(firstLine(t.Body), 60))
+	}
+
+	if v.err != nil {
+		s += fmt.Sprintf("\nError: %v\n", v.err)
+	} else if v.status != "" {
+		s += "\n" + v.status + "\n"
+	}
+
+	s += "\nNavigation: ↑/k (up), ↓/j (down), a (add), e (edit), d (delete), h (history), i (import), x (export), Esc (back)"
+	return s
+}
+
+func (v *FeedbackLibraryView) historyView() string {
+	t := v.library.Templates[v.selected]
+	s := fmt.Sprintf("History of %q\n\n", t.Name)
+	s += fmt.Sprintf("  v%-3d %-16s %s (current)\n", t.Version, formatUpdated(t.UpdatedAt), truncate(firstLine(t.Body), 60))
+	for i := range t.History {
+		r := t.History[len(t.History)-1-i]
+		cursor := " "
+		if i == v.historySelected {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s v%-3d %-16s %s\n", cursor, r.Version, formatUpdated(r.UpdatedAt), truncate(firstLine(r.Body), 60))
+	}
+	if len(t.History) == 0 {
+		s += "\nNo earlier versions.\n"
+	} else {
+		r := t.History[len(t.History)-1-v.historySelected]
+		s += fmt.Sprintf("\nVersion %d:\n%s\n", r.Version, r.Body)
+	}
+	if v.err != nil {
+		s += fmt.Sprintf("\nError: %v\n", v.err)
+	}
+	s += "\nNavigation: ↑/k (up), ↓/j (down), r (restore as new version), Esc (back)"
+	return s
+}
+
+// formatUpdated shows when a version was saved, blank for versions saved
+// before the library tracked it.
+func formatUpdated(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func (v *FeedbackLibraryView) startEdit(t feedback.Template) {
+	v.mode = feedbackModeEdit
+	v.editing = t.Name
+	v.editField = 0
+	v.err = nil
+	v.nameInput.SetValue(t.Name)
+	v.bodyInput.SetValue(t.Body)
+	v.bodyInput.Blur()
+	v.nameInput.Focus()
+}
+
+func (v *FeedbackLibraryView) startPath(mode feedbackMode) {
+	v.mode = mode
+	v.err = nil
+	v.pathInput.SetValue("feedback.yaml")
+	v.pathInput.Focus()
+}
+
+func (v *FeedbackLibraryView) save(status string) {
+	if err := v.library.Save(v.path); err != nil {
+		logger.With("component", "feedback_library_view").Error("Failed to save feedback library", "error", err)
+		v.err = err
+		return
+	}
+	v.err = nil
+	v.status = status
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
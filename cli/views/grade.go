@@ -0,0 +1,286 @@
+package views
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/feedback"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+type gradeField int
+
+const (
+	fieldScore gradeField = iota
+	fieldFeedback
+	fieldComment
+)
+
+type GradeView struct {
+	submission api.Submission
+	templates  []feedback.Template
+	focus      gradeField
+	feedback   int
+	submitting bool
+	err        error
+	// For score and comment input
+	scoreInput   textinput.Model
+	commentInput textarea.Model
+}
+
+func NewGradeView(submission api.Submission) *GradeView {
+	log := logger.With(
+		"component", "grade_view",
+		"submission_id", submission.ID,
+		"assignment_id", submission.AssignmentID,
+	)
+	log.Info("Creating new grade view")
+
+	si := textinput.New()
+	si.Placeholder = fmt.Sprintf("0-%g", submission.Assignment.PointsPossible)
+	si.CharLimit = 8
+	si.Width = 10
+	si.Focus()
+
+	ci := textarea.New()
+	ci.Placeholder = "Comment for the student..."
+	ci.SetWidth(70)
+	ci.SetHeight(5)
+
+	library, err := feedback.Load(feedback.DefaultPath())
+	if err != nil {
+		log.Error("Failed to load feedback library, using defaults", "error", err)
+		library = feedback.DefaultLibrary()
+	}
+
+	return &GradeView{
+		submission:   submission,
+		templates:    library.Templates,
+		focus:        fieldScore,
+		scoreInput:   si,
+		commentInput: ci,
+	}
+}
+
+func (v *GradeView) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// CapturingInput keeps the global quit key from firing while typing.
+func (v *GradeView) CapturingInput() bool {
+	return true
+}
+
+func (v *GradeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With(
+		"component", "grade_view",
+		"submission_id", v.submission.ID,
+		"assignment_id", v.submission.AssignmentID,
+	)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			log.Info("Returning to to grade view")
+			return v, func() tea.Msg {
+				return ToGradeView{}
+			}
+		case "tab", "shift+tab":
+			step := 1
+			if msg.String() == "shift+tab" {
+				step = 2
+			}
+			v.setFocus((v.focus + gradeField(step)) % 3)
+			return v, nil
+		case "ctrl+s":
+			if v.submitting {
+				return v, nil
+			}
+			req, err := v.request()
+			if err != nil {
+				v.err = err
+				return v, nil
+			}
+			v.err = nil
+			v.submitting = true
+			return v, v.submitGrade(req)
+		}
+
+		if v.focus == fieldFeedback {
+			switch msg.String() {
+			case "up", "k":
+				if v.feedback > 0 {
+					v.feedback--
+				}
+			case "down", "j":
+				if v.feedback < len(v.templates)-1 {
+					v.feedback++
+				}
+			case "enter":
+				if len(v.templates) > 0 {
+					v.insertComment(v.templates[v.feedback].Render(feedback.Vars{
+						FirstName:      feedback.FirstName(v.submission.User.Name),
+						AssignmentName: v.submission.Assignment.Name,
+						DueDate:        v.submission.Assignment.DueAt,
+					}))
+				}
+			}
+			return v, nil
+		}
+	case gradeSubmittedMsg:
+		log.Info("Submission graded")
+		return v, func() tea.Msg {
+			return ToGradeView{}
+		}
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.submitting = false
+		v.err = msg
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	switch v.focus {
+	case fieldScore:
+		v.scoreInput, cmd = v.scoreInput.Update(msg)
+	case fieldComment:
+		v.commentInput, cmd = v.commentInput.Update(msg)
+	}
+	return v, cmd
+}
+
+func (v *GradeView) View() string {
+	s := fmt.Sprintf("Grade: %s\n", v.submission.Assignment.Name)
+	s += fmt.Sprintf("Student: %s\n", v.submission.User.Name)
+	if v.submission.SubmittedAt != nil {
+		s += fmt.Sprintf("Submitted: %s", v.submission.SubmittedAt.Local().Format("2006-01-02 15:04"))
+		if v.submission.Late {
+			s += " (LATE)"
+		}
+		s += "\n"
+	}
+	if v.submission.URL != "" {
+		s += fmt.Sprintf("URL: %s\n", v.submission.URL)
+	}
+	s += "\n"
+
+	s += v.label(fieldScore, fmt.Sprintf("Score (out of %g):", v.submission.Assignment.PointsPossible))
+	s += v.scoreInput.View() + "\n\n"
+
+	s += v.label(fieldFeedback, "Canned feedback (enter to insert):")
+	if len(v.templates) == 0 {
+		s += "  No templates, add some from Feedback Library on the home screen.\n"
+	}
+	for i, template := range v.templates {
+		cursor := " "
+		if v.focus == fieldFeedback && v.feedback == i {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %s\n", cursor, template.Name)
+	}
+	s += "\n"
+
+	s += v.label(fieldComment, "Comment:")
+	s += v.commentInput.View() + "\n\n"
+
+	if v.submitting {
+		s += "Submitting grade...\n\n"
+	}
+	if v.err != nil {
+		s += fmt.Sprintf("Error: %v\n\n", v.err)
+	}
+
+	s += "Tab (next field), Ctrl+S (submit), Esc (back)"
+	return s
+}
+
+func (v *GradeView) label(field gradeField, text string) string {
+	if v.focus == field {
+		return "» " + text + "\n"
+	}
+	return "  " + text + "\n"
+}
+
+func (v *GradeView) setFocus(field gradeField) {
+	v.focus = field
+	v.scoreInput.Blur()
+	v.commentInput.Blur()
+	switch field {
+	case fieldScore:
+		v.scoreInput.Focus()
+	case fieldComment:
+		v.commentInput.Focus()
+	}
+}
+
+func (v *GradeView) insertComment(text string) {
+	current := strings.TrimRight(v.commentInput.Value(), "\n ")
+	if current != "" {
+		current += "\n"
+	}
+	v.commentInput.SetValue(current + text)
+}
+
+// request validates the form, a blank score only sends the comment.
+func (v *GradeView) request() (api.GradeSubmissionRequest, error) {
+	req := api.GradeSubmissionRequest{
+		Comment: strings.TrimSpace(v.commentInput.Value()),
+	}
+
+	score, err := parseScore(v.scoreInput.Value(), v.submission.Assignment.PointsPossible)
+	if err != nil {
+		return req, err
+	}
+	req.Score = score
+
+	if req.Score == nil && req.Comment == "" {
+		return req, fmt.Errorf("enter a score or a comment")
+	}
+	return req, nil
+}
+
+func (v *GradeView) submitGrade(req api.GradeSubmissionRequest) tea.Cmd {
+	submission := v.submission
+	return func() tea.Msg {
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+		if err := client.GradeSubmission(submission.AssignmentID, submission.UserID, req); err != nil {
+			return errMsg(err)
+		}
+		return gradeSubmittedMsg{}
+	}
+}
+
+// parseScore returns nil for a blank value and rejects anything outside
+// 0..pointsPossible.
+func parseScore(value string, pointsPossible float64) (*float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(score) || math.IsInf(score, 0) {
+		return nil, fmt.Errorf("invalid score %q", value)
+	}
+	if score < 0 || score > pointsPossible {
+		return nil, fmt.Errorf("score must be between 0 and %g", pointsPossible)
+	}
+	return &score, nil
+}
+
+// Message types
+type gradeSubmittedMsg struct{}
+
+type SubmissionSelectedMsg struct {
+	Submission api.Submission
+}
@@ -0,0 +1,332 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const (
+	heatmapRows = 20
+	heatmapCols = 12
+)
+
+var heatmapStyles = map[api.SubmissionStatus]lipgloss.Style{
+	api.StatusGraded:      lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+	api.StatusSubmitted:   lipgloss.NewStyle().Foreground(lipgloss.Color("4")),
+	api.StatusLate:        lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+	api.StatusMissing:     lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true),
+	api.StatusExcused:     lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+	api.StatusUnsubmitted: lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+}
+
+var heatmapSymbols = map[api.SubmissionStatus]string{
+	api.StatusGraded:      "✓",
+	api.StatusSubmitted:   "S",
+	api.StatusLate:        "L",
+	api.StatusMissing:     "M",
+	api.StatusExcused:     "E",
+	api.StatusUnsubmitted: "·",
+}
+
+type heatmapKey struct {
+	userID       int
+	assignmentID int
+}
+
+type HeatmapView struct {
+	module      api.Module
+	students    []api.Enrollment
+	assignments []api.Lesson
+	submissions map[heatmapKey]api.Submission
+	row         int
+	col         int
+	detail      bool
+	loaded      bool
+	err         error
+}
+
+func NewHeatmapView(module api.Module) *HeatmapView {
+	log := logger.With("component", "heatmap_view", "module_name", module.Name)
+	log.Info("Creating new heatmap view")
+	return &HeatmapView{
+		module:      module,
+		submissions: map[heatmapKey]api.Submission{},
+	}
+}
+
+func (v *HeatmapView) Init() tea.Cmd {
+	log := logger.With("component", "heatmap_view", "module_name", v.module.Name)
+	log.Info("Initializing heatmap view")
+	return v.fetchHeatmap
+}
+
+func (v *HeatmapView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "heatmap_view", "module_name", v.module.Name)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.row > 0 {
+				v.row--
+			}
+		case "down", "j":
+			if v.row < len(v.students)-1 {
+				v.row++
+			}
+		case "left", "h":
+			if v.col > 0 {
+				v.col--
+			}
+		case "right", "l":
+			if v.col < len(v.assignments)-1 {
+				v.col++
+			}
+		case "enter":
+			if len(v.students) > 0 && len(v.assignments) > 0 {
+				v.detail = !v.detail
+			}
+		case "esc":
+			if v.detail {
+				v.detail = false
+				return v, nil
+			}
+			log.Info("Returning to module view")
+			return v, func() tea.Msg {
+				return ModuleSelectedMsg{Module: v.module}
+			}
+		}
+	case heatmapMsg:
+		log.Info("Received heatmap",
+			"students", len(msg.students),
+			"assignments", len(msg.assignments),
+			"submissions", len(msg.submissions))
+		v.students = msg.students
+		v.assignments = msg.assignments
+		v.submissions = msg.submissions
+		v.loaded = true
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *HeatmapView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+
+	if !v.loaded {
+		return fmt.Sprintf("Loading submissions for module: %s...", v.module.Name)
+	}
+
+	if len(v.students) == 0 || len(v.assignments) == 0 {
+		return fmt.Sprintf("Module %s has no students or assignments to show.\n\nPress esc to go back, q to quit.", v.module.Name)
+	}
+
+	rowStart, rowEnd := pageBounds(v.row, len(v.students), heatmapRows)
+	colStart, colEnd := pageBounds(v.col, len(v.assignments), heatmapCols)
+
+	s := fmt.Sprintf("Missing Work: %s (%d students × %d assignments)\n\n", v.module.Name, len(v.students), len(v.assignments))
+
+	// Column headers
+	s += fmt.Sprintf("  %-24s", "")
+	for c := colStart; c < colEnd; c++ {
+		s += fmt.Sprintf("  %-3s", fmt.Sprintf("A%d", c+1))
+	}
+	s += "  Missing\n"
+
+	for r := rowStart; r < rowEnd; r++ {
+		student := v.students[r]
+		cursor := " "
+		if r == v.row {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %-24s", cursor, truncate(student.User.Name, 24))
+		for c := colStart; c < colEnd; c++ {
+			status := v.status(student, v.assignments[c])
+			cell := heatmapStyles[status].Render(fmt.Sprintf(" %-3s", heatmapSymbols[status]))
+			if r == v.row && c == v.col {
+				cell = heatmapStyles[status].Reverse(true).Render(fmt.Sprintf(" %-3s", heatmapSymbols[status]))
+			}
+			s += " " + cell
+		}
+		s += fmt.Sprintf("  %d\n", v.missingForStudent(student))
+	}
+
+	// Column totals make it obvious which assignments everyone is skipping
+	s += fmt.Sprintf("  %-24s", "Missing")
+	for c := colStart; c < colEnd; c++ {
+		s += fmt.Sprintf("  %-3d", v.missingForAssignment(v.assignments[c]))
+	}
+	s += "\n\n"
+
+	s += fmt.Sprintf("Rows %d-%d of %d, columns A%d-A%d of %d\n", rowStart+1, rowEnd, len(v.students), colStart+1, colEnd, len(v.assignments))
+	s += fmt.Sprintf("A%d: %s\n", v.col+1, v.assignments[v.col].Title)
+
+	if v.detail {
+		s += "\n" + v.formatDetail() + "\n"
+	}
+
+	legend := []string{}
+	for _, status := range []api.SubmissionStatus{api.StatusGraded, api.StatusSubmitted, api.StatusLate, api.StatusMissing, api.StatusExcused, api.StatusUnsubmitted} {
+		legend = append(legend, heatmapStyles[status].Render(heatmapSymbols[status])+" "+string(status))
+	}
+	s += "\n" + strings.Join(legend, "  ") + "\n"
+	s += "\nNavigation: ↑↓←→/hjkl (move), Enter (details), Esc (back), q (quit)"
+	return s
+}
+
+func (v *HeatmapView) formatDetail() string {
+	student := v.students[v.row]
+	assignment := v.assignments[v.col]
+	submission, ok := v.submissions[heatmapKey{student.User.ID, assignment.ContentID}]
+
+	s := fmt.Sprintf("%s — %s\n", student.User.Name, assignment.Title)
+	if !ok {
+		return s + "  No submission record"
+	}
+
+	s += fmt.Sprintf("  Status:    %s\n", submission.Status())
+	if submission.SubmittedAt != nil {
+		s += fmt.Sprintf("  Submitted: %s\n", submission.SubmittedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if submission.Score != nil {
+		s += fmt.Sprintf("  Score:     %g\n", *submission.Score)
+	}
+	if submission.Attempt > 0 {
+		s += fmt.Sprintf("  Attempt:   %d\n", submission.Attempt)
+	}
+	if submission.URL != "" {
+		s += fmt.Sprintf("  URL:       %s\n", submission.URL)
+	}
+	if submission.PreviewURL != "" {
+		s += fmt.Sprintf("  Preview:   %s", submission.PreviewURL)
+	}
+	return strings.TrimRight(s, "\n")
+}
+
+func (v *HeatmapView) status(student api.Enrollment, assignment api.Lesson) api.SubmissionStatus {
+	submission, ok := v.submissions[heatmapKey{student.User.ID, assignment.ContentID}]
+	if !ok {
+		return api.StatusUnsubmitted
+	}
+	return submission.Status()
+}
+
+func (v *HeatmapView) missingForStudent(student api.Enrollment) int {
+	count := 0
+	for _, assignment := range v.assignments {
+		if v.status(student, assignment) == api.StatusMissing {
+			count++
+		}
+	}
+	return count
+}
+
+func (v *HeatmapView) missingForAssignment(assignment api.Lesson) int {
+	count := 0
+	for _, student := range v.students {
+		if v.status(student, assignment) == api.StatusMissing {
+			count++
+		}
+	}
+	return count
+}
+
+func (v *HeatmapView) fetchHeatmap() tea.Msg {
+	log := logger.With(
+		"component", "heatmap_view",
+		"action", "fetch_heatmap",
+		"module_name", v.module.Name,
+	)
+	log.Info("Fetching heatmap data")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	enrollments, err := client.GetCourseEnrollments()
+	if err != nil {
+		log.Error("Failed to fetch enrollments", "error", err)
+		return errMsg(err)
+	}
+
+	nodes, err := client.GetModuleItems(v.module.ID)
+	if err != nil {
+		log.Error("Failed to fetch module items", "error", err)
+		return errMsg(err)
+	}
+
+	assignments := ModuleAssignments(nodes)
+	submissions := map[heatmapKey]api.Submission{}
+	for _, assignment := range assignments {
+		assignmentSubmissions, err := client.GetAssignmentSubmissions(assignment.ContentID)
+		if err != nil {
+			log.Error("Failed to fetch submissions", "error", err, "assignment_id", assignment.ContentID)
+			return errMsg(err)
+		}
+		for _, submission := range assignmentSubmissions {
+			submissions[heatmapKey{submission.UserID, assignment.ContentID}] = submission
+		}
+	}
+
+	data := heatmapMsg{
+		students:    StudentEnrollments(enrollments),
+		assignments: assignments,
+		submissions: submissions,
+	}
+
+	// Most missing work first, so struggling students are on the first page
+	missing := map[int]int{}
+	for _, student := range data.students {
+		for _, assignment := range assignments {
+			if s, ok := submissions[heatmapKey{student.User.ID, assignment.ContentID}]; ok && s.Status() == api.StatusMissing {
+				missing[student.User.ID]++
+			}
+		}
+	}
+	sort.SliceStable(data.students, func(i, j int) bool {
+		a, b := data.students[i], data.students[j]
+		if missing[a.User.ID] != missing[b.User.ID] {
+			return missing[a.User.ID] > missing[b.User.ID]
+		}
+		return a.User.Name < b.User.Name
+	})
+
+	return data
+}
+
+// ModuleAssignments flattens a module's lessons into its assignments, in the
+// order they appear in the module.
+func ModuleAssignments(nodes []api.ModuleNode) []api.Lesson {
+	var assignments []api.Lesson
+	for _, node := range nodes {
+		for _, lesson := range append([]api.Lesson{node.Lesson}, node.Children...) {
+			if lesson.Type == "Assignment" && lesson.ContentID != 0 {
+				assignments = append(assignments, lesson)
+			}
+		}
+	}
+	return assignments
+}
+
+// Message types
+type heatmapMsg struct {
+	students    []api.Enrollment
+	assignments []api.Lesson
+	submissions map[heatmapKey]api.Submission
+}
+
+type HeatmapSelectedMsg struct {
+	Module api.Module
+}
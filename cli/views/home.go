@@ -34,6 +34,41 @@ func NewHomeView() *HomeView {
 			Description: "View student enrollments and grades",
 			Action:      "enrollments",
 		},
+		{
+			Label:       "To Grade",
+			Description: "Submitted work waiting for a grade",
+			Action:      "to_grade",
+		},
+		{
+			Label:       "New Announcement",
+			Description: "Write an announcement in $EDITOR and post or schedule it",
+			Action:      "announcement",
+		},
+		{
+			Label:       "Feedback Library",
+			Description: "Manage canned comment templates",
+			Action:      "feedback",
+		},
+		{
+			Label:       "Extensions",
+			Description: "Active assignment overrides, with revoke",
+			Action:      "overrides",
+		},
+		{
+			Label:       "Grading Policy",
+			Description: "Assignment group weights and drop rules",
+			Action:      "grading_policy",
+		},
+		{
+			Label:       "GitHub Access",
+			Description: "Audit the cohort team's access to course repositories",
+			Action:      "github",
+		},
+		{
+			Label:       "GitHub Team Sync",
+			Description: "Invite or remove cohort team members to match the roster",
+			Action:      "team_sync",
+		},
 		{
 			Label:       "Quit",
 			Description: "Exit the application",
@@ -80,6 +115,34 @@ func (v *HomeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return v, func() tea.Msg {
 					return EnrollmentsView{}
 				}
+			case "to_grade":
+				return v, func() tea.Msg {
+					return ToGradeView{}
+				}
+			case "announcement":
+				return v, func() tea.Msg {
+					return AnnouncementView{}
+				}
+			case "feedback":
+				return v, func() tea.Msg {
+					return FeedbackLibraryView{}
+				}
+			case "overrides":
+				return v, func() tea.Msg {
+					return OverridesView{}
+				}
+			case "grading_policy":
+				return v, func() tea.Msg {
+					return GradingPolicyView{}
+				}
+			case "github":
+				return v, func() tea.Msg {
+					return GitHubAccessView{}
+				}
+			case "team_sync":
+				return v, func() tea.Msg {
+					return TeamSyncView{}
+				}
 			case "quit":
 				return v, tea.Quit
 			}
@@ -2,15 +2,21 @@ package views
 
 import (
 	"fmt"
+	"html"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/lint"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/markup"
 )
 
+const previewHeight = 20
+
 type LessonView struct {
 	Lesson   api.ModuleNode
 	Module   api.Module
@@ -19,6 +25,21 @@ type LessonView struct {
 	// For date input
 	dateInput     textinput.Model
 	dateInputMode bool
+	// For the content preview
+	previewMode    bool
+	previewItems   []api.Lesson
+	previewIndex   int
+	previewSource  string
+	previewLines   []string
+	previewOffset  int
+	previewLoading bool
+	previewErr     error
+	width          int
+	// For the pre-publish lint
+	lintMode    bool
+	lintLoading bool
+	lintPublish bool
+	findings    []lint.Finding
 }
 
 type LessonAction int
@@ -27,6 +48,9 @@ const (
 	ActionPublish LessonAction = iota
 	ActionUnpublish
 	ActionSetDueDate
+	ActionEditContent
+	ActionPreviewContent
+	ActionLint
 )
 
 var actions = []struct {
@@ -36,6 +60,9 @@ var actions = []struct {
 	{"Publish", ActionPublish},
 	{"Unpublish", ActionUnpublish},
 	{"Set Due Date", ActionSetDueDate},
+	{"Edit Content", ActionEditContent},
+	{"Preview Content", ActionPreviewContent},
+	{"Lint", ActionLint},
 }
 
 func NewLessonView(lesson api.ModuleNode, module api.Module) *LessonView {
@@ -60,6 +87,8 @@ func NewLessonView(lesson api.ModuleNode, module api.Module) *LessonView {
 		selected:      0,
 		dateInput:     ti,
 		dateInputMode: false,
+		previewItems:  PreviewableItems(lesson),
+		width:         80,
 	}
 }
 
@@ -67,6 +96,10 @@ func (v *LessonView) Init() tea.Cmd {
 	return textinput.Blink
 }
 
+func (v *LessonView) CapturingInput() bool {
+	return v.dateInputMode
+}
+
 func (v *LessonView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log := logger.With(
 		"component", "lesson_view",
@@ -103,6 +136,92 @@ func (v *LessonView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return v, cmd
 	}
 
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.width = msg.Width
+		if v.previewMode && !v.previewLoading && v.previewErr == nil {
+			v.renderPreview()
+		}
+		return v, nil
+	case lintedMsg:
+		v.lintLoading = false
+		if msg.err != nil {
+			v.lintMode = false
+			v.err = msg.err
+			return v, nil
+		}
+		v.findings = msg.findings
+		if v.lintPublish && len(v.findings) == 0 {
+			v.lintMode = false
+			return v, func() tea.Msg {
+				return PublishLessonMsg{Lesson: v.Lesson}
+			}
+		}
+		return v, nil
+	case previewLoadedMsg:
+		// A slow response for an item we've since moved away from
+		if msg.index != v.previewIndex {
+			return v, nil
+		}
+		v.previewLoading = false
+		v.previewErr = msg.err
+		v.previewSource = msg.source
+		v.previewOffset = 0
+		if msg.err == nil {
+			v.renderPreview()
+		}
+		return v, nil
+	}
+
+	if v.lintMode {
+		if msg, ok := msg.(tea.KeyMsg); ok && !v.lintLoading {
+			switch msg.String() {
+			case "y":
+				if v.lintPublish {
+					v.lintMode = false
+					return v, func() tea.Msg {
+						return PublishLessonMsg{Lesson: v.Lesson}
+					}
+				}
+			case "esc", "n":
+				v.lintMode = false
+			}
+		}
+		return v, nil
+	}
+
+	if v.previewMode {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "up", "k":
+				if v.previewOffset > 0 {
+					v.previewOffset--
+				}
+			case "down", "j":
+				if v.previewOffset < len(v.previewLines)-previewHeight {
+					v.previewOffset++
+				}
+			case "pgup":
+				v.previewOffset = max(v.previewOffset-previewHeight, 0)
+			case "pgdown", " ":
+				v.previewOffset = max(min(v.previewOffset+previewHeight, len(v.previewLines)-previewHeight), 0)
+			case "tab", "right", "l":
+				if len(v.previewItems) > 1 {
+					v.previewIndex = (v.previewIndex + 1) % len(v.previewItems)
+					return v, v.loadPreview()
+				}
+			case "shift+tab", "left", "h":
+				if len(v.previewItems) > 1 {
+					v.previewIndex = (v.previewIndex + len(v.previewItems) - 1) % len(v.previewItems)
+					return v, v.loadPreview()
+				}
+			case "esc":
+				v.previewMode = false
+			}
+		}
+		return v, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -120,9 +239,9 @@ func (v *LessonView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			switch action {
 			case ActionPublish:
-				return v, func() tea.Msg {
-					return PublishLessonMsg{Lesson: v.Lesson}
-				}
+				return v, v.runLint(true)
+			case ActionLint:
+				return v, v.runLint(false)
 			case ActionUnpublish:
 				return v, func() tea.Msg {
 					return UnpublishLessonMsg{Lesson: v.Lesson}
@@ -131,6 +250,17 @@ func (v *LessonView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				v.dateInputMode = true
 				v.dateInput.Focus()
 				return v, textinput.Blink
+			case ActionEditContent:
+				return v, func() tea.Msg {
+					return EditContentMsg{Lesson: v.Lesson, Module: v.Module}
+				}
+			case ActionPreviewContent:
+				if len(v.previewItems) == 0 {
+					v.err = fmt.Errorf("%s has no pages, assignments or links to preview", v.Lesson.Lesson.Title)
+					return v, nil
+				}
+				v.previewMode = true
+				return v, v.loadPreview()
 			}
 		case "esc":
 			log.Info("Returning to module view")
@@ -207,6 +337,14 @@ func (v *LessonView) View() string {
 		)
 	}
 
+	if v.lintMode {
+		return v.lintView()
+	}
+
+	if v.previewMode {
+		return v.previewView()
+	}
+
 	s := fmt.Sprintf("Lesson: %s\n\n", v.Lesson.Lesson.Title)
 	s += "Select an action:\n\n"
 	for i, action := range actions {
@@ -220,6 +358,150 @@ func (v *LessonView) View() string {
 	return s
 }
 
+func (v *LessonView) lintView() string {
+	if v.lintLoading {
+		return fmt.Sprintf("Linting %s...", v.Lesson.Lesson.Title)
+	}
+
+	errors, warnings := lint.Count(v.findings)
+	s := fmt.Sprintf("Lint: %s (%d errors, %d warnings)\n\n", v.Lesson.Lesson.Title, errors, warnings)
+	if len(v.findings) == 0 {
+		s += "No problems found.\n"
+	}
+	for _, f := range v.findings {
+		label := "warning"
+		if f.Severity == lint.SeverityError {
+			label = "ERROR  "
+		}
+		s += fmt.Sprintf("%s %-30s %s\n", label, truncate(f.Item, 30), f.Message)
+	}
+
+	if v.lintPublish {
+		return s + "\nPress y to publish anyway, esc to cancel."
+	}
+	return s + "\nPress esc to go back."
+}
+
+// runLint lints the lesson against the current state of its module. When
+// publish is set a clean result publishes straight away.
+func (v *LessonView) runLint(publish bool) tea.Cmd {
+	v.lintMode = true
+	v.lintLoading = true
+	v.lintPublish = publish
+	v.findings = nil
+
+	module := v.Module
+	lessonID := v.Lesson.Lesson.ID
+	return func() tea.Msg {
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+		nodes, err := client.GetModuleItems(module.ID)
+		if err != nil {
+			return lintedMsg{err: err}
+		}
+		for i, node := range nodes {
+			if node.Lesson.ID == lessonID {
+				findings, err := lint.LintLesson(client, module, nodes, i)
+				return lintedMsg{findings: findings, err: err}
+			}
+		}
+		return lintedMsg{err: fmt.Errorf("lesson %d is no longer in %s", lessonID, module.Name)}
+	}
+}
+
+func (v *LessonView) previewView() string {
+	item := v.previewItems[v.previewIndex]
+	s := fmt.Sprintf("Preview: %s (%s, %d of %d)\n\n", item.Title, item.Type, v.previewIndex+1, len(v.previewItems))
+
+	switch {
+	case v.previewLoading:
+		s += "Loading content...\n"
+	case v.previewErr != nil:
+		s += fmt.Sprintf("Error: %v\n", v.previewErr)
+	case len(v.previewLines) == 0:
+		s += "(no content)\n"
+	default:
+		end := min(v.previewOffset+previewHeight, len(v.previewLines))
+		for _, line := range v.previewLines[v.previewOffset:end] {
+			s += line + "\n"
+		}
+		if len(v.previewLines) > previewHeight {
+			s += fmt.Sprintf("\n  lines %d-%d of %d\n", v.previewOffset+1, end, len(v.previewLines))
+		}
+	}
+
+	s += "\nPress j/k or pgup/pgdown to scroll, tab to switch items, esc to go back."
+	return s
+}
+
+// loadPreview fetches the selected preview item. Responses carry the item's
+// index so one that arrives after moving on is dropped.
+func (v *LessonView) loadPreview() tea.Cmd {
+	v.previewLoading = true
+	v.previewErr = nil
+	index := v.previewIndex
+	item := v.previewItems[index]
+	return func() tea.Msg {
+		if item.Type == "ExternalUrl" {
+			url := html.EscapeString(item.ExternalURL)
+			return previewLoadedMsg{index: index, source: fmt.Sprintf(`<p>External link: <a href="%[1]s">%[1]s</a></p>`, url)}
+		}
+		content, err := FetchLessonContent(item)
+		if err != nil {
+			return previewLoadedMsg{index: index, err: err}
+		}
+		return previewLoadedMsg{index: index, source: content}
+	}
+}
+
+// renderPreview renders the fetched item at the current width, so resizing
+// doesn't fetch it again.
+func (v *LessonView) renderPreview() {
+	v.previewLines = nil
+	rendered, err := markup.Render(v.previewSource, v.width)
+	if err != nil {
+		v.previewErr = err
+		return
+	}
+	if rendered != "" {
+		v.previewLines = strings.Split(rendered, "\n")
+	}
+	v.previewOffset = min(v.previewOffset, max(len(v.previewLines)-previewHeight, 0))
+}
+
+// PreviewableItems returns the lesson item and its children that have content
+// to preview: pages, assignments and external links.
+func PreviewableItems(lesson api.ModuleNode) []api.Lesson {
+	items := EditableItems(lesson)
+	var previewable []api.Lesson
+	for _, item := range append([]api.Lesson{lesson.Lesson}, lesson.Children...) {
+		if item.Type == "ExternalUrl" && item.ExternalURL != "" {
+			previewable = append(previewable, item)
+			continue
+		}
+		for _, editable := range items {
+			if editable.ID == item.ID {
+				previewable = append(previewable, item)
+				break
+			}
+		}
+	}
+	return previewable
+}
+
+type lintedMsg struct {
+	findings []lint.Finding
+	err      error
+}
+
+type previewLoadedMsg struct {
+	index  int
+	source string
+	err    error
+}
+
 type PublishLessonMsg struct {
 	Lesson api.ModuleNode
 }
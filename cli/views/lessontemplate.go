@@ -0,0 +1,375 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/templates"
+)
+
+type newLessonMode int
+
+const (
+	newLessonModeTemplate newLessonMode = iota
+	newLessonModeFill
+	newLessonModePosition
+	newLessonModeConfirm
+	newLessonModeSaving
+	newLessonModeDone
+)
+
+var blockNumberPattern = regexp.MustCompile(`^Block (\d+)`)
+
+// LessonTemplateView creates a lesson in a module from a template, filling in
+// its placeholders and placing it before the lesson picked.
+type LessonTemplateView struct {
+	module    api.Module
+	lessons   []api.ModuleNode
+	templates []templates.Template
+	template  templates.Template
+	selected  int
+	mode      newLessonMode
+
+	placeholders []string
+	values       map[string]string
+	fillIndex    int
+	input        textinput.Model
+
+	position int
+	items    []api.NewModuleItem
+	status   string
+	err      error
+}
+
+type LessonTemplateMsg struct {
+	Module  api.Module
+	Lessons []api.ModuleNode
+}
+
+func NewLessonTemplateView(module api.Module, lessons []api.ModuleNode) *LessonTemplateView {
+	log := logger.With("component", "lesson_template_view", "module_name", module.Name)
+	log.Info("Creating new lesson template view")
+
+	ti := textinput.New()
+	ti.CharLimit = 200
+	ti.Width = 60
+
+	return &LessonTemplateView{
+		module:   module,
+		lessons:  lessons,
+		values:   map[string]string{},
+		input:    ti,
+		position: len(lessons),
+	}
+}
+
+func (v *LessonTemplateView) Init() tea.Cmd {
+	log := logger.With("component", "lesson_template_view", "module_name", v.module.Name)
+	log.Info("Initializing lesson template view")
+	return v.loadTemplates
+}
+
+func (v *LessonTemplateView) CapturingInput() bool {
+	return v.mode == newLessonModeFill
+}
+
+func (v *LessonTemplateView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "lesson_template_view", "module_name", v.module.Name)
+
+	switch msg := msg.(type) {
+	case templatesMsg:
+		log.Info("Loaded lesson templates", "count", len(msg))
+		v.templates = msg
+		return v, nil
+	case lessonCreatedMsg:
+		if msg.err != nil {
+			log.Error("Failed to create lesson", "error", msg.err)
+			v.status = fmt.Sprintf("Failed to create the lesson: %v", msg.err)
+		} else {
+			log.Info("Created lesson", "items", len(v.items))
+			v.status = fmt.Sprintf("Created %q with %d items.", v.items[0].Title, len(v.items))
+		}
+		v.mode = newLessonModeDone
+		return v, nil
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+		return v, nil
+	case tea.KeyMsg:
+		if v.err != nil {
+			if msg.String() == "esc" {
+				return v, v.backToModule
+			}
+			return v, nil
+		}
+		switch v.mode {
+		case newLessonModeTemplate:
+			return v.updateTemplate(msg)
+		case newLessonModeFill:
+			return v.updateFill(msg)
+		case newLessonModePosition:
+			return v.updatePosition(msg)
+		case newLessonModeConfirm:
+			switch msg.String() {
+			case "y":
+				log.Info("Creating lesson from template", "template", v.template.Name, "position", v.requestPosition())
+				v.mode = newLessonModeSaving
+				return v, v.createLesson(api.CreateModuleItemsRequest{Position: v.requestPosition(), Items: v.items})
+			case "n", "esc":
+				v.mode = newLessonModePosition
+			}
+		case newLessonModeDone:
+			switch msg.String() {
+			case "enter", "esc":
+				return v, v.backToModule
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func (v *LessonTemplateView) updateTemplate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+		}
+	case "down", "j":
+		if v.selected < len(v.templates)-1 {
+			v.selected++
+		}
+	case "enter":
+		if len(v.templates) == 0 {
+			return v, nil
+		}
+		v.template = v.templates[v.selected]
+		v.placeholders = v.template.Placeholders()
+		if _, ok := v.values["block"]; !ok {
+			v.values["block"] = nextBlockNumber(v.lessons)
+		}
+		if len(v.placeholders) == 0 {
+			return v.fillDone()
+		}
+		v.fillIndex = 0
+		return v, v.editPlaceholder()
+	case "esc":
+		return v, v.backToModule
+	}
+	return v, nil
+}
+
+func (v *LessonTemplateView) updateFill(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		value := strings.TrimSpace(v.input.Value())
+		if value == "" {
+			v.status = fmt.Sprintf("Enter a value for %s.", v.placeholders[v.fillIndex])
+			return v, nil
+		}
+		v.values[v.placeholders[v.fillIndex]] = value
+		if v.fillIndex == len(v.placeholders)-1 {
+			v.input.Blur()
+			return v.fillDone()
+		}
+		v.fillIndex++
+		return v, v.editPlaceholder()
+	case "esc":
+		if v.fillIndex == 0 {
+			v.input.Blur()
+			v.mode = newLessonModeTemplate
+			v.status = ""
+			return v, nil
+		}
+		v.fillIndex--
+		return v, v.editPlaceholder()
+	}
+
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return v, cmd
+}
+
+func (v *LessonTemplateView) editPlaceholder() tea.Cmd {
+	v.mode = newLessonModeFill
+	v.status = ""
+	v.input.Placeholder = v.placeholders[v.fillIndex]
+	v.input.SetValue(v.values[v.placeholders[v.fillIndex]])
+	v.input.CursorEnd()
+	v.input.Focus()
+	return textinput.Blink
+}
+
+func (v *LessonTemplateView) fillDone() (tea.Model, tea.Cmd) {
+	items, err := v.template.Fill(v.values)
+	if err != nil {
+		v.status = err.Error()
+		return v, nil
+	}
+	v.items = items
+	v.mode = newLessonModePosition
+	v.status = ""
+	return v, nil
+}
+
+func (v *LessonTemplateView) updatePosition(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if v.position > 0 {
+			v.position--
+		}
+	case "down", "j":
+		if v.position < len(v.lessons) {
+			v.position++
+		}
+	case "enter":
+		v.mode = newLessonModeConfirm
+	case "esc":
+		if len(v.placeholders) == 0 {
+			v.mode = newLessonModeTemplate
+			return v, nil
+		}
+		v.fillIndex = len(v.placeholders) - 1
+		return v, v.editPlaceholder()
+	}
+	return v, nil
+}
+
+// positionLabel describes where choice i puts the lesson: before lesson i,
+// or at the end of the module.
+func (v *LessonTemplateView) positionLabel(i int) string {
+	if i >= len(v.lessons) {
+		return "At the end of the module"
+	}
+	return "Before " + v.lessons[i].Lesson.Title
+}
+
+// requestPosition is the module position of the lesson picked, where the new
+// lesson's header goes, or 0 to add it at the end.
+func (v *LessonTemplateView) requestPosition() int {
+	if v.position >= len(v.lessons) {
+		return 0
+	}
+	return v.lessons[v.position].Lesson.Position
+}
+
+// nextBlockNumber follows the highest "Block N" lesson in the module.
+func nextBlockNumber(lessons []api.ModuleNode) string {
+	highest := 0
+	for _, lesson := range lessons {
+		if match := blockNumberPattern.FindStringSubmatch(lesson.Lesson.Title); match != nil {
+			if n, err := strconv.Atoi(match[1]); err == nil && n > highest {
+				highest = n
+			}
+		}
+	}
+	return strconv.Itoa(highest + 1)
+}
+
+func (v *LessonTemplateView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+	if v.templates == nil {
+		return "Loading lesson templates..."
+	}
+
+	s := fmt.Sprintf("New lesson in %s\n", v.module.Name)
+	s += "====================\n\n"
+
+	switch v.mode {
+	case newLessonModeTemplate:
+		s += "Choose a template:\n\n"
+		for i, t := range v.templates {
+			cursor := " "
+			if i == v.selected {
+				cursor = ">"
+			}
+			s += fmt.Sprintf("%s %s (%d items)\n", cursor, t.Name, len(t.Items))
+			if t.Description != "" {
+				s += fmt.Sprintf("    %s\n", t.Description)
+			}
+		}
+		s += fmt.Sprintf("\nTemplates are read from %s\n\n", templates.DefaultDir())
+		if v.status != "" {
+			s += v.status + "\n\n"
+		}
+		return s + "Press enter to choose, esc to go back, q to quit."
+	case newLessonModeFill:
+		s += fmt.Sprintf("Template: %s (%d of %d)\n\n", v.template.Name, v.fillIndex+1, len(v.placeholders))
+		s += fmt.Sprintf("%s:\n%s\n\n", v.placeholders[v.fillIndex], v.input.View())
+		if v.status != "" {
+			s += v.status + "\n\n"
+		}
+		return s + "Press enter for the next value, esc to go back"
+	case newLessonModePosition:
+		s += fmt.Sprintf("Where should %q go?\n\n", v.items[0].Title)
+		for i := 0; i <= len(v.lessons); i++ {
+			cursor := " "
+			if i == v.position {
+				cursor = ">"
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, v.positionLabel(i))
+		}
+		return s + "\nPress enter to choose, esc to go back, q to quit."
+	}
+
+	s += fmt.Sprintf("%s:\n\n", v.positionLabel(v.position))
+	for _, item := range v.items {
+		s += fmt.Sprintf("  %s%s (%s)\n", strings.Repeat("  ", item.Indent), item.Title, item.Type)
+		if item.URL != "" {
+			s += fmt.Sprintf("  %s  %s\n", strings.Repeat("  ", item.Indent), item.URL)
+		}
+	}
+	s += "\n"
+
+	switch v.mode {
+	case newLessonModeConfirm:
+		s += "Pages and assignments are created unpublished, like the lesson.\n"
+		return s + "Create this lesson? (y/n)"
+	case newLessonModeSaving:
+		return s + "Creating lesson..."
+	}
+	return s + v.status + "\n\nPress enter or esc to go back to the module."
+}
+
+func (v *LessonTemplateView) loadTemplates() tea.Msg {
+	log := logger.With("component", "lesson_template_view", "action", "load_templates")
+	dir := templates.DefaultDir()
+	log.Info("Loading lesson templates", "dir", dir)
+
+	loaded, err := templates.LoadAll(dir)
+	if err != nil {
+		log.Error("Failed to load lesson templates", "error", err)
+		return errMsg(err)
+	}
+	return templatesMsg(loaded)
+}
+
+func (v *LessonTemplateView) createLesson(req api.CreateModuleItemsRequest) tea.Cmd {
+	return func() tea.Msg {
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+		_, err := client.CreateModuleItems(v.module.ID, req)
+		return lessonCreatedMsg{err: err}
+	}
+}
+
+func (v *LessonTemplateView) backToModule() tea.Msg {
+	return ModuleSelectedMsg{Module: v.module}
+}
+
+// Message types
+type templatesMsg []templates.Template
+
+type lessonCreatedMsg struct {
+	err error
+}
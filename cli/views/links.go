@@ -0,0 +1,273 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/export"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/linkcheck"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const linkPageSize = 15
+
+// LinkCheckView collects the links in a module, checks them and lists the
+// ones that are dead, private or redirected.
+type LinkCheckView struct {
+	module         api.Module
+	links          []linkcheck.Link
+	results        []linkcheck.Result
+	showAll        bool
+	selected       int
+	loading        string
+	err            error
+	exportMode     bool
+	exportSelected int
+	status         string
+}
+
+func NewLinkCheckView(module api.Module) *LinkCheckView {
+	log := logger.With("component", "link_check_view", "module_name", module.Name)
+	log.Info("Creating new link check view")
+	return &LinkCheckView{
+		module:  module,
+		loading: "Collecting links",
+	}
+}
+
+func (v *LinkCheckView) Init() tea.Cmd {
+	return v.collectLinks
+}
+
+func (v *LinkCheckView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "link_check_view", "module_name", v.module.Name)
+
+	if v.exportMode {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "up", "k":
+				if v.exportSelected > 0 {
+					v.exportSelected--
+				}
+			case "down", "j":
+				if v.exportSelected < len(export.Formats)-1 {
+					v.exportSelected++
+				}
+			case "enter":
+				v.exportMode = false
+				return v, v.writeReport(export.Formats[v.exportSelected])
+			case "esc":
+				v.exportMode = false
+			}
+		}
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.visible())-1 {
+				v.selected++
+			}
+		case "a":
+			v.showAll = !v.showAll
+			v.selected = 0
+		case "r":
+			if v.loading == "" {
+				v.loading = "Collecting links"
+				v.results = nil
+				v.status = ""
+				return v, v.collectLinks
+			}
+		case "e":
+			if v.loading == "" {
+				v.exportMode = true
+				v.status = ""
+			}
+		case "esc":
+			return v, func() tea.Msg {
+				return ModuleSelectedMsg{Module: v.module}
+			}
+		}
+	case linksCollectedMsg:
+		log.Info("Collected links", "count", len(msg))
+		v.links = msg
+		v.loading = fmt.Sprintf("Checking %d links", len(msg))
+		return v, v.checkLinks
+	case linksCheckedMsg:
+		log.Info("Checked links", "count", len(msg), "problems", len(linkcheck.Problems(msg)))
+		v.results = msg
+		v.loading = ""
+		v.selected = 0
+	case linkReportMsg:
+		log.Info("Wrote link report", "path", msg.path)
+		v.status = fmt.Sprintf("Wrote %d links to %s", msg.count, msg.path)
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.loading = ""
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *LinkCheckView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+
+	if v.loading != "" {
+		return fmt.Sprintf("%s in %s...", v.loading, v.module.Name)
+	}
+
+	if v.exportMode {
+		s := fmt.Sprintf("Write report of %d links as:\n\n", len(v.visible()))
+		for i, format := range export.Formats {
+			cursor := " "
+			if v.exportSelected == i {
+				cursor = ">"
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, format.Label())
+		}
+		s += "\nPress enter to write, esc to cancel."
+		return s
+	}
+
+	counts := map[linkcheck.Status]int{}
+	for _, r := range v.results {
+		counts[r.Status]++
+	}
+	s := fmt.Sprintf("Links in %s: %d ok, %d dead, %d private or missing, %d private, %d redirected\n\n",
+		v.module.Name,
+		counts[linkcheck.StatusOK],
+		counts[linkcheck.StatusDead],
+		counts[linkcheck.StatusPrivateOrMissing],
+		counts[linkcheck.StatusPrivate],
+		counts[linkcheck.StatusRedirected],
+	)
+
+	visible := v.visible()
+	if len(visible) == 0 {
+		s += "No broken links.\n"
+	}
+	start, end := pageBounds(v.selected, len(visible), linkPageSize)
+	for i := start; i < end; i++ {
+		r := visible[i]
+		cursor := " "
+		if v.selected == i {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %-18s %s\n", cursor, r.Status, truncate(r.URL, 70))
+	}
+
+	if len(visible) > 0 {
+		r := visible[v.selected]
+		s += "\n"
+		if r.StatusCode != 0 {
+			s += fmt.Sprintf("Status:   %d\n", r.StatusCode)
+		}
+		if r.Location != "" {
+			s += fmt.Sprintf("Moved to: %s\n", r.Location)
+		}
+		if r.Error != "" {
+			s += fmt.Sprintf("Error:    %s\n", r.Error)
+		}
+		s += fmt.Sprintf("Found in: %s\n", firstN(r.Sources, 3))
+	}
+
+	if v.status != "" {
+		s += "\n" + v.status + "\n"
+	}
+
+	toggle := "a to show all links"
+	if v.showAll {
+		toggle = "a to show problems only"
+	}
+	s += fmt.Sprintf("\nPress %s, e to write a report, r to recheck, esc to go back, q to quit.", toggle)
+	return s
+}
+
+func (v *LinkCheckView) visible() []linkcheck.Result {
+	if v.showAll {
+		return v.results
+	}
+	return linkcheck.Problems(v.results)
+}
+
+func (v *LinkCheckView) collectLinks() tea.Msg {
+	log := logger.With("component", "link_check_view", "action", "collect_links", "module_name", v.module.Name)
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	nodes, err := client.GetModuleItems(v.module.ID)
+	if err != nil {
+		log.Error("Failed to fetch module items", "error", err)
+		return errMsg(err)
+	}
+	links, err := linkcheck.Collect(client, nodes)
+	if err != nil {
+		log.Error("Failed to collect links", "error", err)
+		return errMsg(err)
+	}
+	return linksCollectedMsg(links)
+}
+
+func (v *LinkCheckView) checkLinks() tea.Msg {
+	var opts linkcheck.Options
+	if gh, err := github.NewClientFromEnv(); err == nil {
+		opts.GitHub = gh
+	}
+	return linksCheckedMsg(linkcheck.Check(context.Background(), v.links, opts))
+}
+
+func (v *LinkCheckView) writeReport(format export.Format) tea.Cmd {
+	results := v.visible()
+	module := v.module
+	return func() tea.Msg {
+		path := fmt.Sprintf("links-%d-%s.%s", module.ID, time.Now().Format("2006-01-02"), format)
+		file, err := os.Create(path)
+		if err != nil {
+			return errMsg(fmt.Errorf("failed to create %s: %w", path, err))
+		}
+		defer file.Close()
+
+		if err := export.WriteLinkReport(file, format, results); err != nil {
+			return errMsg(err)
+		}
+		return linkReportMsg{path: path, count: len(results)}
+	}
+}
+
+// firstN joins up to n items, noting how many more there are.
+func firstN(items []string, n int) string {
+	if len(items) <= n {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:n], ", "), len(items)-n)
+}
+
+// Message types
+type linksCollectedMsg []linkcheck.Link
+type linksCheckedMsg []linkcheck.Result
+
+type linkReportMsg struct {
+	path  string
+	count int
+}
+
+type LinkCheckSelectedMsg struct {
+	Module api.Module
+}
@@ -0,0 +1,351 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/feedback"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+type messageMode int
+
+const (
+	messageModeCompose messageMode = iota
+	messageModeLoading
+	messageModePreview
+	messageModeSending
+	messageModeDone
+)
+
+type messageField int
+
+const (
+	fieldTemplate messageField = iota
+	fieldSubject
+	fieldBody
+)
+
+type renderedMessage struct {
+	enrollment api.Enrollment
+	subject    string
+	body       string
+}
+
+// MessageView composes a Canvas conversation from a template and sends one
+// rendered copy to each recipient, after previewing every message.
+type MessageView struct {
+	recipients []api.Enrollment
+	back       tea.Msg
+	mode       messageMode
+	focus      messageField
+	template   int
+	messages   []renderedMessage
+	preview    int
+	failed     []string
+	err        error
+	// For subject and body input
+	subjectInput textinput.Model
+	bodyInput    textarea.Model
+}
+
+func NewMessageView(recipients []api.Enrollment, back tea.Msg) *MessageView {
+	log := logger.With("component", "message_view", "recipients", len(recipients))
+	log.Info("Creating new message view")
+
+	si := textinput.New()
+	si.Placeholder = "Subject"
+	si.CharLimit = 255
+	si.Width = 60
+
+	bi := textarea.New()
+	bi.Placeholder = "Hi {{first_name}}, ..."
+	bi.SetWidth(70)
+	bi.SetHeight(8)
+
+	v := &MessageView{
+		recipients:   recipients,
+		back:         back,
+		focus:        fieldTemplate,
+		subjectInput: si,
+		bodyInput:    bi,
+	}
+	v.loadTemplate(0)
+	return v
+}
+
+func (v *MessageView) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (v *MessageView) CapturingInput() bool {
+	return v.mode == messageModeCompose && v.focus != fieldTemplate
+}
+
+func (v *MessageView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "message_view", "recipients", len(v.recipients))
+
+	switch msg := msg.(type) {
+	case renderedMessagesMsg:
+		log.Info("Rendered messages", "count", len(msg))
+		v.messages = msg
+		v.preview = 0
+		v.mode = messageModePreview
+		return v, nil
+	case messagesSentMsg:
+		log.Info("Sent messages", "sent", len(v.messages)-len(msg.failed), "failed", len(msg.failed))
+		v.failed = msg.failed
+		v.mode = messageModeDone
+		return v, nil
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+		v.mode = messageModeCompose
+		return v, nil
+	}
+
+	switch v.mode {
+	case messageModePreview:
+		return v.updatePreview(msg)
+	case messageModeDone:
+		if msg, ok := msg.(tea.KeyMsg); ok && (msg.String() == "esc" || msg.String() == "enter") {
+			return v, v.goBack
+		}
+		return v, nil
+	case messageModeLoading, messageModeSending:
+		return v, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			log.Info("Cancelled message")
+			return v, v.goBack
+		case "tab", "shift+tab":
+			step := 1
+			if msg.String() == "shift+tab" {
+				step = 2
+			}
+			v.setFocus((v.focus + messageField(step)) % 3)
+			return v, nil
+		case "ctrl+p":
+			if strings.TrimSpace(v.bodyInput.Value()) == "" {
+				v.err = fmt.Errorf("message body is empty")
+				return v, nil
+			}
+			v.err = nil
+			v.mode = messageModeLoading
+			return v, v.renderMessages
+		}
+
+		if v.focus == fieldTemplate {
+			switch msg.String() {
+			case "up", "k":
+				if v.template > 0 {
+					v.template--
+				}
+			case "down", "j":
+				if v.template < len(feedback.MessageTemplates)-1 {
+					v.template++
+				}
+			case "enter":
+				v.loadTemplate(v.template)
+			}
+			return v, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch v.focus {
+	case fieldSubject:
+		v.subjectInput, cmd = v.subjectInput.Update(msg)
+	case fieldBody:
+		v.bodyInput, cmd = v.bodyInput.Update(msg)
+	}
+	return v, cmd
+}
+
+func (v *MessageView) updatePreview(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "left", "h":
+			if v.preview > 0 {
+				v.preview--
+			}
+		case "right", "l":
+			if v.preview < len(v.messages)-1 {
+				v.preview++
+			}
+		case "esc":
+			v.mode = messageModeCompose
+		case "ctrl+s":
+			v.mode = messageModeSending
+			return v, v.sendMessages
+		}
+	}
+	return v, nil
+}
+
+func (v *MessageView) View() string {
+	switch v.mode {
+	case messageModeLoading:
+		return fmt.Sprintf("Rendering %d message(s)...", len(v.recipients))
+	case messageModeSending:
+		return fmt.Sprintf("Sending %d message(s)...", len(v.messages))
+	case messageModeDone:
+		s := fmt.Sprintf("Sent %d of %d message(s).\n", len(v.messages)-len(v.failed), len(v.messages))
+		if len(v.failed) > 0 {
+			s += "\nFailed:\n"
+			for _, failure := range v.failed {
+				s += "  - " + failure + "\n"
+			}
+		}
+		return s + "\nPress enter to continue."
+	case messageModePreview:
+		m := v.messages[v.preview]
+		s := fmt.Sprintf("Preview %d of %d\n\n", v.preview+1, len(v.messages))
+		s += fmt.Sprintf("To:      %s\n", m.enrollment.User.Name)
+		s += fmt.Sprintf("Subject: %s\n", m.subject)
+		s += strings.Repeat("─", 70) + "\n"
+		s += m.body + "\n"
+		s += strings.Repeat("─", 70) + "\n\n"
+		s += fmt.Sprintf("←/h (previous), →/l (next), Ctrl+S (send all %d), Esc (edit)", len(v.messages))
+		return s
+	}
+
+	s := fmt.Sprintf("Message %s\n\n", v.recipientSummary())
+
+	s += v.label(fieldTemplate, "Template (enter to load):")
+	for i, template := range feedback.MessageTemplates {
+		cursor := " "
+		if v.focus == fieldTemplate && v.template == i {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %s\n", cursor, template.Name)
+	}
+	s += "\n"
+	s += v.label(fieldSubject, "Subject:")
+	s += v.subjectInput.View() + "\n\n"
+	s += v.label(fieldBody, "Body:")
+	s += v.bodyInput.View() + "\n\n"
+	s += "Placeholders: " + strings.Join(feedback.MessagePlaceholders, ", ") + "\n\n"
+
+	if v.err != nil {
+		s += fmt.Sprintf("Error: %v\n\n", v.err)
+	}
+	s += "Tab (next field), Ctrl+P (preview), Esc (cancel)"
+	return s
+}
+
+func (v *MessageView) label(field messageField, text string) string {
+	if v.focus == field {
+		return "» " + text + "\n"
+	}
+	return "  " + text + "\n"
+}
+
+func (v *MessageView) recipientSummary() string {
+	if len(v.recipients) == 1 {
+		return v.recipients[0].User.Name
+	}
+	return fmt.Sprintf("%d students", len(v.recipients))
+}
+
+func (v *MessageView) setFocus(field messageField) {
+	v.focus = field
+	v.subjectInput.Blur()
+	v.bodyInput.Blur()
+	switch field {
+	case fieldSubject:
+		v.subjectInput.Focus()
+	case fieldBody:
+		v.bodyInput.Focus()
+	}
+}
+
+func (v *MessageView) loadTemplate(i int) {
+	template := feedback.MessageTemplates[i]
+	v.subjectInput.SetValue(template.Subject)
+	v.bodyInput.SetValue(template.Body)
+}
+
+func (v *MessageView) goBack() tea.Msg {
+	return v.back
+}
+
+// renderMessages fills in the template for every recipient, fetching their
+// missing work only when the template asks for it.
+func (v *MessageView) renderMessages() tea.Msg {
+	log := logger.With("component", "message_view", "action", "render_messages")
+
+	subject := strings.TrimSpace(v.subjectInput.Value())
+	body := strings.TrimSpace(v.bodyInput.Value())
+	needsMissing := strings.Contains(subject+body, "{{missing_")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	var messages []renderedMessage
+	for _, enrollment := range v.recipients {
+		vars := feedback.MessageVars{
+			FirstName:    feedback.FirstName(enrollment.User.Name),
+			CurrentScore: enrollment.Grades.Score,
+		}
+		if needsMissing {
+			missing, err := client.GetMissingAssignments(enrollment.User.ID)
+			if err != nil {
+				log.Error("Failed to fetch missing assignments", "error", err, "user_id", enrollment.User.ID)
+				return errMsg(err)
+			}
+			for _, assignment := range missing {
+				vars.Missing = append(vars.Missing, assignment.Name)
+			}
+		}
+
+		messages = append(messages, renderedMessage{
+			enrollment: enrollment,
+			subject:    feedback.RenderMessage(subject, vars),
+			body:       feedback.RenderMessage(body, vars),
+		})
+	}
+	return renderedMessagesMsg(messages)
+}
+
+// sendMessages sends each message separately so one failure doesn't stop
+// the rest, and reports who didn't get theirs.
+func (v *MessageView) sendMessages() tea.Msg {
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	var failed []string
+	for _, m := range v.messages {
+		err := client.SendConversation(api.SendConversationRequest{
+			Recipients: []int{m.enrollment.User.ID},
+			Subject:    m.subject,
+			Body:       m.body,
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", m.enrollment.User.Name, err))
+		}
+	}
+	return messagesSentMsg{failed: failed}
+}
+
+// Message types
+type renderedMessagesMsg []renderedMessage
+
+type messagesSentMsg struct {
+	failed []string
+}
+
+type ComposeMessageMsg struct {
+	Recipients []api.Enrollment
+	Back       tea.Msg
+}
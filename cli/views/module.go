@@ -3,17 +3,32 @@ package views
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
 )
 
+const arrangePageSize = 15
+
 type ModuleView struct {
 	module   api.Module
 	lessons  []api.ModuleNode
 	selected int
 	err      error
+
+	// Rearranging items, with the lesson tree previewed before saving
+	arranging       bool
+	arrange         arrangement
+	original        arrangement
+	arrangeSelected int
+	targets         []api.Module
+	picking         bool
+	pickSelected    int
+	confirm         bool
+	saving          bool
+	status          string
 }
 
 func NewModuleView(module api.Module) *ModuleView {
@@ -35,6 +50,28 @@ func (v *ModuleView) Init() tea.Cmd {
 func (v *ModuleView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log := logger.With("component", "module_view", "module_name", v.module.Name)
 
+	switch msg := msg.(type) {
+	case moveTargetsMsg:
+		v.targets = msg
+		return v, nil
+	case layoutSavedMsg:
+		log.Info("Saved module layout", "lessons", len(msg.lessons))
+		v.saving = false
+		if msg.err != nil {
+			v.status = fmt.Sprintf("Failed to save: %v", msg.err)
+			return v, nil
+		}
+		v.lessons = msg.lessons
+		v.selected = min(v.selected, max(len(v.lessons)-1, 0))
+		v.arranging = false
+		v.status = "Saved the new layout."
+		return v, nil
+	case tea.KeyMsg:
+		if v.arranging {
+			return v.updateArrange(msg)
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -60,6 +97,33 @@ func (v *ModuleView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+		case "h":
+			log.Info("Opening missing work heatmap")
+			return v, func() tea.Msg {
+				return HeatmapSelectedMsg{Module: v.module}
+			}
+		case "c":
+			log.Info("Opening link checker")
+			return v, func() tea.Msg {
+				return LinkCheckSelectedMsg{Module: v.module}
+			}
+		case "n":
+			log.Info("Opening new lesson from template")
+			return v, func() tea.Msg {
+				return LessonTemplateMsg{Module: v.module, Lessons: v.lessons}
+			}
+		case "o":
+			if len(v.lessons) > 0 {
+				log.Info("Rearranging module items")
+				v.arranging = true
+				v.arrange = newArrangement(v.lessons)
+				v.original = newArrangement(v.lessons)
+				v.arrangeSelected = 0
+				v.status = ""
+				if v.targets == nil {
+					return v, v.fetchMoveTargets
+				}
+			}
 		case "esc":
 			log.Info("Returning to home view")
 			return v, func() tea.Msg {
@@ -86,6 +150,10 @@ func (v *ModuleView) View() string {
 		return fmt.Sprintf("Loading lessons for module: %s...", v.module.Name)
 	}
 
+	if v.arranging {
+		return v.arrangeView()
+	}
+
 	s := fmt.Sprintf("Module: %s\n\n", v.module.Name)
 	s += "Select a lesson:\n\n"
 	for i, lesson := range v.lessons {
@@ -95,10 +163,216 @@ func (v *ModuleView) View() string {
 		}
 		s += fmt.Sprintf("%s %s (%s)\n", cursor, lesson.Lesson.Title, lesson.Lesson.Type)
 	}
-	s += "\nPress esc to go back, q to quit."
+	if v.status != "" {
+		s += "\n" + v.status + "\n"
+	}
+	s += "\nPress h for the missing work heatmap, c to check links, n for a new lesson from a template, o to rearrange items, esc to go back, q to quit."
+	return s
+}
+
+func (v *ModuleView) updateArrange(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "module_view", "module_name", v.module.Name)
+	if v.saving {
+		return v, nil
+	}
+
+	if v.confirm {
+		switch msg.String() {
+		case "y":
+			v.confirm = false
+			v.saving = true
+			log.Info("Saving module layout", "items", len(v.arrange.items), "moves", len(v.arrange.moves))
+			return v, v.saveLayout(v.arrange.request())
+		case "n", "esc":
+			v.confirm = false
+		}
+		return v, nil
+	}
+
+	if v.picking {
+		targets := v.moveTargets()
+		switch msg.String() {
+		case "up", "k":
+			if v.pickSelected > 0 {
+				v.pickSelected--
+			}
+		case "down", "j":
+			if v.pickSelected < len(targets)-1 {
+				v.pickSelected++
+			}
+		case "enter":
+			if len(targets) > 0 {
+				v.arrange.moveTo(v.arrangeSelected, targets[v.pickSelected])
+				v.arrangeSelected = min(v.arrangeSelected, max(len(v.arrange.items)-1, 0))
+			}
+			v.picking = false
+		case "esc":
+			v.picking = false
+		}
+		return v, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if v.arrangeSelected > 0 {
+			v.arrangeSelected--
+		}
+	case "down", "j":
+		if v.arrangeSelected < len(v.arrange.items)-1 {
+			v.arrangeSelected++
+		}
+	case "K", "shift+up":
+		v.arrangeSelected = v.arrange.moveUp(v.arrangeSelected)
+	case "J", "shift+down":
+		v.arrangeSelected = v.arrange.moveDown(v.arrangeSelected)
+	case ">", "tab":
+		v.arrange.indent(v.arrangeSelected)
+	case "<", "shift+tab":
+		v.arrange.outdent(v.arrangeSelected)
+	case "m":
+		if len(v.arrange.items) == 0 {
+			return v, nil
+		}
+		if len(v.moveTargets()) == 0 {
+			v.status = "There are no other modules to move to."
+			return v, nil
+		}
+		v.picking = true
+		v.pickSelected = 0
+		v.status = ""
+	case "u":
+		v.arrange = newArrangement(v.lessons)
+		v.arrangeSelected = 0
+		v.status = "Undid all changes."
+	case "s":
+		if !v.arrange.changed(v.original) {
+			v.status = "Nothing to save."
+			return v, nil
+		}
+		v.confirm = true
+		v.status = ""
+	case "esc":
+		log.Info("Stopped rearranging module items")
+		v.arranging = false
+		v.status = ""
+	case "q", "ctrl+c":
+		return v, tea.Quit
+	}
+	return v, nil
+}
+
+// moveTargets are the modules an item can be moved to.
+func (v *ModuleView) moveTargets() []api.Module {
+	var targets []api.Module
+	for _, m := range v.targets {
+		if m.ID != v.module.ID {
+			targets = append(targets, m)
+		}
+	}
+	return targets
+}
+
+func (v *ModuleView) arrangeView() string {
+	s := fmt.Sprintf("Rearrange: %s\n\n", v.module.Name)
+
+	if v.picking {
+		item := v.arrange.items[v.arrangeSelected]
+		s += fmt.Sprintf("Move %q to:\n\n", item.Title)
+		for i, m := range v.moveTargets() {
+			cursor := " "
+			if i == v.pickSelected {
+				cursor = ">"
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, m.Name)
+		}
+		return s + "\nIt goes to the end of that module. Press enter to choose, esc to cancel."
+	}
+
+	s += "Items:\n"
+	if len(v.arrange.items) == 0 {
+		s += "  No items left in this module.\n"
+	} else {
+		start, end := pageBounds(v.arrangeSelected, len(v.arrange.items), arrangePageSize)
+		for i := start; i < end; i++ {
+			item := v.arrange.items[i]
+			cursor := " "
+			if i == v.arrangeSelected {
+				cursor = ">"
+			}
+			changed := " "
+			if v.arrange.itemChanged(i, v.original) {
+				changed = "*"
+			}
+			s += fmt.Sprintf("%s%s %s%s (%s)\n", cursor, changed, strings.Repeat("  ", item.Indent), truncate(item.Title, 50), item.Type)
+		}
+		if end-start < len(v.arrange.items) {
+			s += fmt.Sprintf("  %d-%d of %d\n", start+1, end, len(v.arrange.items))
+		}
+	}
+
+	s += "\nLessons after saving:\n"
+	current := v.arrange.lessonOf(v.arrangeSelected)
+	for i, lesson := range v.arrange.lessons() {
+		marker := " "
+		if i == current {
+			marker = ">"
+		}
+		s += fmt.Sprintf("%s %d. %s (%d items)\n", marker, i+1, lesson.Lesson.Title, len(lesson.Children))
+		if i == current {
+			for _, child := range lesson.Children {
+				s += fmt.Sprintf("       - %s\n", child.Title)
+			}
+		}
+	}
+	if orphans := v.arrange.orphans(); orphans > 0 {
+		s += fmt.Sprintf("\n! %d item(s) before the first lesson won't belong to any lesson.\n", orphans)
+	}
+	for _, move := range v.arrange.moves {
+		s += fmt.Sprintf("\nMoving %q to %s", move.item.Title, move.module.Name)
+	}
+	if len(v.arrange.moves) > 0 {
+		s += "\n"
+	}
+	s += "\n"
+
+	switch {
+	case v.saving:
+		return s + "Saving..."
+	case v.confirm:
+		return s + "Save this layout to Canvas? (y/n)"
+	}
+	if v.status != "" {
+		s += v.status + "\n\n"
+	}
+	s += "Keys: ↑/k ↓/j select, K/J move up/down, >/< indent/outdent, m move to module, u undo, s save\n"
+	s += "Press esc to stop rearranging without saving, q to quit."
 	return s
 }
 
+func (v *ModuleView) fetchMoveTargets() tea.Msg {
+	log := logger.With("component", "module_view", "action", "fetch_move_targets")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+	modules, err := client.GetModules()
+	if err != nil {
+		log.Error("Failed to fetch modules", "error", err)
+		return moveTargetsMsg{}
+	}
+	return moveTargetsMsg(modules)
+}
+
+func (v *ModuleView) saveLayout(req api.UpdateModuleLayoutRequest) tea.Cmd {
+	return func() tea.Msg {
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+		lessons, err := client.UpdateModuleLayout(v.module.ID, req)
+		return layoutSavedMsg{lessons: lessons, err: err}
+	}
+}
+
 func (v *ModuleView) fetchLessons() tea.Msg {
 	log := logger.With(
 		"component", "module_view",
@@ -119,6 +393,12 @@ func (v *ModuleView) fetchLessons() tea.Msg {
 }
 
 type lessonsMsg []api.ModuleNode
+type moveTargetsMsg []api.Module
+
+type layoutSavedMsg struct {
+	lessons []api.ModuleNode
+	err     error
+}
 
 type LessonSelectedMsg struct {
 	Lesson api.ModuleNode
@@ -3,24 +3,50 @@ package views
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
 	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
 )
 
+type modulesMode int
+
+const (
+	modulesModeList modulesMode = iota
+	modulesModeDetail
+	modulesModeUnlock
+	modulesModePrerequisites
+)
+
 type ModulesView struct {
 	modules  []api.Module
 	selected int
 	err      error
+
+	// Module details and settings
+	mode           modulesMode
+	unlockInput    textinput.Model
+	prerequisites  map[int]bool
+	prereqSelected int
+	saving         bool
+	status         string
 }
 
 func NewModulesView() *ModulesView {
 	log := logger.With("component", "modules_view")
 	log.Info("Creating new modules view")
+	ti := textinput.New()
+	ti.Placeholder = "YYYY-MM-DD [HH:MM], empty to clear"
+	ti.CharLimit = 16
+	ti.Width = 36
+
 	return &ModulesView{
-		modules:  []api.Module{},
-		selected: 0,
+		modules:     []api.Module{},
+		selected:    0,
+		unlockInput: ti,
 	}
 }
 
@@ -30,9 +56,40 @@ func (v *ModulesView) Init() tea.Cmd {
 	return v.fetchModules
 }
 
+func (v *ModulesView) CapturingInput() bool {
+	return v.mode == modulesModeUnlock
+}
+
 func (v *ModulesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log := logger.With("component", "modules_view")
 
+	switch msg := msg.(type) {
+	case moduleUpdatedMsg:
+		log.Info("Module updated", "module_id", msg.ID)
+		v.saving = false
+		v.status = "Saved."
+		for i := range v.modules {
+			if v.modules[i].ID == msg.ID {
+				v.modules[i] = api.Module(msg)
+			}
+		}
+		return v, nil
+	case moduleUpdateFailedMsg:
+		log.Error("Failed to update module", "error", msg.err)
+		v.saving = false
+		v.status = fmt.Sprintf("Failed to save: %v", msg.err)
+		return v, nil
+	case tea.KeyMsg:
+		switch v.mode {
+		case modulesModeDetail:
+			return v.updateDetail(msg)
+		case modulesModeUnlock:
+			return v.updateUnlock(msg)
+		case modulesModePrerequisites:
+			return v.updatePrerequisites(msg)
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -54,6 +111,11 @@ func (v *ModulesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return ModuleSelectedMsg{Module: selectedModule}
 				}
 			}
+		case "d":
+			if len(v.modules) > 0 {
+				v.mode = modulesModeDetail
+				v.status = ""
+			}
 		case "esc":
 			log.Info("Returning to home view")
 			return v, func() tea.Msg {
@@ -86,18 +148,268 @@ func (v *ModulesView) View() string {
 	s += "==============\n\n"
 	s += "Select a module to view its contents:\n\n"
 
+	if v.mode != modulesModeList {
+		return v.detailView()
+	}
+
 	for i, module := range v.modules {
 		cursor := "  "
 		if v.selected == i {
 			cursor = "> "
 		}
-		s += fmt.Sprintf("%s%s\n", cursor, module.Name)
+		s += fmt.Sprintf("%s%s%s\n", cursor, module.Name, moduleFlags(module))
+	}
+
+	s += "\nNavigation: ↑/k (up), ↓/j (down), Enter (select), d (details), Esc (back), q (quit)"
+	return s
+}
+
+// moduleFlags notes the settings that keep students out of a module.
+func moduleFlags(m api.Module) string {
+	var flags []string
+	if !m.Published {
+		flags = append(flags, "unpublished")
+	}
+	if m.UnlockAt != nil && m.UnlockAt.After(time.Now()) {
+		flags = append(flags, "locked until "+m.UnlockAt.Local().Format("Jan 2"))
+	}
+	if len(m.PrerequisiteModuleIDs) > 0 {
+		flags = append(flags, "has prerequisites")
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(flags, ", ") + ")"
+}
+
+func (v *ModulesView) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "modules_view")
+	if v.saving {
+		return v, nil
+	}
+
+	module := v.modules[v.selected]
+	switch msg.String() {
+	case "u":
+		v.mode = modulesModeUnlock
+		v.status = ""
+		v.unlockInput.SetValue("")
+		if module.UnlockAt != nil {
+			v.unlockInput.SetValue(module.UnlockAt.Local().Format(dateTimeLayout))
+		}
+		v.unlockInput.Focus()
+		return v, textinput.Blink
+	case "p":
+		if len(v.prerequisiteCandidates()) == 0 {
+			v.status = "The first module can't have prerequisites."
+			return v, nil
+		}
+		v.mode = modulesModePrerequisites
+		v.status = ""
+		v.prereqSelected = 0
+		v.prerequisites = map[int]bool{}
+		for _, id := range module.PrerequisiteModuleIDs {
+			v.prerequisites[id] = true
+		}
+	case "s":
+		req := moduleSettings(module)
+		req.RequireSequentialProgress = !req.RequireSequentialProgress
+		log.Info("Toggling sequential progress", "module_id", module.ID, "require", req.RequireSequentialProgress)
+		v.saving = true
+		return v, v.updateModule(module.ID, req)
+	case "b":
+		req := moduleSettings(module)
+		req.Published = !req.Published
+		log.Info("Toggling published", "module_id", module.ID, "published", req.Published)
+		v.saving = true
+		return v, v.updateModule(module.ID, req)
+	case "enter":
+		return v, func() tea.Msg {
+			return ModuleSelectedMsg{Module: module}
+		}
+	case "esc":
+		v.mode = modulesModeList
+		v.status = ""
+	case "q", "ctrl+c":
+		return v, tea.Quit
+	}
+	return v, nil
+}
+
+func (v *ModulesView) updateUnlock(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		value := strings.TrimSpace(v.unlockInput.Value())
+		req := moduleSettings(v.modules[v.selected])
+		req.UnlockAt = nil
+		if value != "" {
+			unlockAt, err := parseDateTime(value, 0)
+			if err != nil {
+				v.status = err.Error()
+				return v, nil
+			}
+			req.UnlockAt = &unlockAt
+		}
+		v.mode = modulesModeDetail
+		v.saving = true
+		return v, v.updateModule(v.modules[v.selected].ID, req)
+	case "esc":
+		v.mode = modulesModeDetail
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	v.unlockInput, cmd = v.unlockInput.Update(msg)
+	return v, cmd
+}
+
+func (v *ModulesView) updatePrerequisites(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	candidates := v.prerequisiteCandidates()
+	switch msg.String() {
+	case "up", "k":
+		if v.prereqSelected > 0 {
+			v.prereqSelected--
+		}
+	case "down", "j":
+		if v.prereqSelected < len(candidates)-1 {
+			v.prereqSelected++
+		}
+	case " ", "x":
+		id := candidates[v.prereqSelected].ID
+		v.prerequisites[id] = !v.prerequisites[id]
+	case "enter":
+		req := moduleSettings(v.modules[v.selected])
+		req.PrerequisiteModuleIDs = []int{}
+		for _, m := range candidates {
+			if v.prerequisites[m.ID] {
+				req.PrerequisiteModuleIDs = append(req.PrerequisiteModuleIDs, m.ID)
+			}
+		}
+		v.mode = modulesModeDetail
+		v.saving = true
+		return v, v.updateModule(v.modules[v.selected].ID, req)
+	case "esc":
+		v.mode = modulesModeDetail
+	case "q", "ctrl+c":
+		return v, tea.Quit
+	}
+	return v, nil
+}
+
+// prerequisiteCandidates are the modules before the selected one, the only
+// ones Canvas accepts as prerequisites.
+func (v *ModulesView) prerequisiteCandidates() []api.Module {
+	current := v.modules[v.selected]
+	var candidates []api.Module
+	for _, m := range v.modules {
+		if m.Position < current.Position {
+			candidates = append(candidates, m)
+		}
+	}
+	return candidates
+}
+
+func (v *ModulesView) moduleName(id int) string {
+	for _, m := range v.modules {
+		if m.ID == id {
+			return m.Name
+		}
+	}
+	return fmt.Sprintf("module %d", id)
+}
+
+func (v *ModulesView) detailView() string {
+	m := v.modules[v.selected]
+	s := fmt.Sprintf("Module: %s\n", m.Name)
+	s += strings.Repeat("=", len("Module: ")+len(m.Name)) + "\n\n"
+
+	published := "no"
+	if m.Published {
+		published = "yes"
+	}
+	unlock := "always open"
+	if m.UnlockAt != nil {
+		unlock = m.UnlockAt.Local().Format("Mon Jan 2 2006 15:04")
+	}
+	sequential := "no"
+	if m.RequireSequentialProgress {
+		sequential = "yes, items must be completed in order"
+	}
+	var prerequisites []string
+	for _, id := range m.PrerequisiteModuleIDs {
+		prerequisites = append(prerequisites, v.moduleName(id))
+	}
+	if len(prerequisites) == 0 {
+		prerequisites = []string{"none"}
+	}
+
+	s += fmt.Sprintf("  Position:      %d\n", m.Position)
+	s += fmt.Sprintf("  Items:         %d\n", m.ItemsCount)
+	s += fmt.Sprintf("  Published:     %s\n", published)
+	s += fmt.Sprintf("  Unlocks:       %s\n", unlock)
+	s += fmt.Sprintf("  Prerequisites: %s\n", strings.Join(prerequisites, ", "))
+	s += fmt.Sprintf("  Sequential:    %s\n\n", sequential)
+
+	switch v.mode {
+	case modulesModeUnlock:
+		s += fmt.Sprintf("Unlock date:\n%s\n\n", v.unlockInput.View())
+		if v.status != "" {
+			s += v.status + "\n\n"
+		}
+		return s + "Press enter to save, esc to cancel"
+	case modulesModePrerequisites:
+		s += "Prerequisites (modules students must complete first):\n\n"
+		for i, candidate := range v.prerequisiteCandidates() {
+			cursor := " "
+			if i == v.prereqSelected {
+				cursor = ">"
+			}
+			check := "[ ]"
+			if v.prerequisites[candidate.ID] {
+				check = "[x]"
+			}
+			s += fmt.Sprintf("%s %s %s\n", cursor, check, candidate.Name)
+		}
+		return s + "\nPress space to toggle, enter to save, esc to cancel"
 	}
 
-	s += "\nNavigation: ↑/k (up), ↓/j (down), Enter (select), Esc (back), q (quit)"
+	if v.saving {
+		return s + "Saving..."
+	}
+	if v.status != "" {
+		s += v.status + "\n\n"
+	}
+	s += "Keys: u unlock date, p prerequisites, s toggle sequential, b toggle published, enter open\n"
+	s += "Press esc to go back, q to quit."
 	return s
 }
 
+// moduleSettings is the module's current settings, ready to change one.
+func moduleSettings(m api.Module) api.UpdateModuleRequest {
+	return api.UpdateModuleRequest{
+		UnlockAt:                  m.UnlockAt,
+		PrerequisiteModuleIDs:     m.PrerequisiteModuleIDs,
+		RequireSequentialProgress: m.RequireSequentialProgress,
+		Published:                 m.Published,
+	}
+}
+
+func (v *ModulesView) updateModule(moduleID int, req api.UpdateModuleRequest) tea.Cmd {
+	return func() tea.Msg {
+		log := logger.With("component", "modules_view", "action", "update_module", "module_id", moduleID)
+		log.Info("Updating module settings")
+
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+		module, err := client.UpdateModule(moduleID, req)
+		if err != nil {
+			return moduleUpdateFailedMsg{err: err}
+		}
+		return moduleUpdatedMsg(module)
+	}
+}
+
 func (v *ModulesView) fetchModules() tea.Msg {
 	log := logger.With("component", "modules_view", "action", "fetch_modules")
 	log.Info("Fetching modules from API")
@@ -116,9 +428,20 @@ func (v *ModulesView) fetchModules() tea.Msg {
 // Message types
 type modulesMsg []api.Module
 type errMsg error
+type moduleUpdatedMsg api.Module
+
+type moduleUpdateFailedMsg struct {
+	err error
+}
 
 type ModuleSelectedMsg struct {
 	Module api.Module
 }
 
 type NavigateToHomeMsg struct{}
+
+// InputCapturer is implemented by views that take free text, so keys like q
+// reach the input instead of quitting the application.
+type InputCapturer interface {
+	CapturingInput() bool
+}
@@ -0,0 +1,245 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const overridesPageSize = 15
+
+// OverridesView lists the assignment overrides in the course, active ones
+// by default, and revokes them.
+type OverridesView struct {
+	overrides []api.AssignmentOverride
+	names     map[int]string
+	showAll   bool
+	selected  int
+	confirm   bool
+	status    string
+	loaded    bool
+	err       error
+}
+
+func NewOverridesView() *OverridesView {
+	log := logger.With("component", "overrides_view")
+	log.Info("Creating new overrides view")
+	return &OverridesView{}
+}
+
+func (v *OverridesView) Init() tea.Cmd {
+	log := logger.With("component", "overrides_view")
+	log.Info("Initializing overrides view")
+	return v.fetchOverrides
+}
+
+// visible are the overrides shown with the current filter.
+func (v *OverridesView) visible() []api.AssignmentOverride {
+	if v.showAll {
+		return v.overrides
+	}
+	now := time.Now()
+	var active []api.AssignmentOverride
+	for _, o := range v.overrides {
+		if o.Active(now) {
+			active = append(active, o)
+		}
+	}
+	return active
+}
+
+func (v *OverridesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "overrides_view")
+
+	if v.confirm {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "y":
+				v.confirm = false
+				override := v.visible()[v.selected]
+				log.Info("Revoking override", "override_id", override.ID, "assignment_id", override.AssignmentID)
+				v.status = fmt.Sprintf("Revoking override on %s...", override.AssignmentName)
+				return v, v.revoke(override)
+			case "n", "esc":
+				v.confirm = false
+			}
+		}
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.visible())-1 {
+				v.selected++
+			}
+		case "a":
+			v.showAll = !v.showAll
+			v.selected = 0
+		case "x":
+			if v.loaded && len(v.visible()) > 0 {
+				v.confirm = true
+				v.status = ""
+			}
+		case "r":
+			v.loaded = false
+			v.status = ""
+			return v, v.fetchOverrides
+		case "esc":
+			log.Info("Returning to home view")
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		}
+	case overridesLoadedMsg:
+		log.Info("Received overrides", "count", len(msg.overrides))
+		v.overrides = msg.overrides
+		v.names = msg.names
+		v.selected = min(v.selected, max(len(v.visible())-1, 0))
+		v.loaded = true
+	case overrideRevokedMsg:
+		if msg.err != nil {
+			v.status = fmt.Sprintf("Failed to revoke override on %s: %v", msg.override.AssignmentName, msg.err)
+			return v, nil
+		}
+		v.status = fmt.Sprintf("Revoked override on %s.", msg.override.AssignmentName)
+		return v, v.fetchOverrides
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+// who names the students an override is for, or falls back to its title
+// for section and group overrides.
+func (v *OverridesView) who(o api.AssignmentOverride) string {
+	if len(o.StudentIDs) == 0 {
+		return o.Title
+	}
+	var names []string
+	for _, id := range o.StudentIDs {
+		if name, ok := v.names[id]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("user %d", id))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (v *OverridesView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+	if !v.loaded {
+		return "Loading assignment overrides..."
+	}
+
+	filter := "active"
+	if v.showAll {
+		filter = "all"
+	}
+	overrides := v.visible()
+	s := fmt.Sprintf("Assignment Overrides (%d %s)\n", len(overrides), filter)
+	s += "==============================\n\n"
+
+	if len(overrides) == 0 {
+		s += "No overrides.\n\n"
+	} else {
+		now := time.Now()
+		s += fmt.Sprintf("  %-30s %-22s %-18s %-18s\n", "Assignment", "For", "Due", "Extended to")
+		start, end := pageBounds(v.selected, len(overrides), overridesPageSize)
+		for i := start; i < end; i++ {
+			o := overrides[i]
+			cursor := " "
+			if i == v.selected {
+				cursor = ">"
+			}
+			expired := ""
+			if !o.Active(now) {
+				expired = " (expired)"
+			}
+			s += fmt.Sprintf("%s %-30s %-22s %-18s %-18s%s\n", cursor, truncate(o.AssignmentName, 30), truncate(v.who(o), 22),
+				formatDue(o.AssignmentDueAt), formatDue(o.DueAt), expired)
+		}
+		s += fmt.Sprintf("\n  %d-%d of %d\n\n", start+1, end, len(overrides))
+	}
+
+	if v.confirm {
+		o := overrides[v.selected]
+		s += fmt.Sprintf("Revoke the override on %s for %s? Their dates go back to the assignment's. (y/n)", o.AssignmentName, v.who(o))
+		return s
+	}
+	if v.status != "" {
+		s += v.status + "\n\n"
+	}
+	s += "Keys: ↑/k ↓/j move, x revoke, a toggle active/all, r refresh\n"
+	s += "Press esc to go back, q to quit."
+	return s
+}
+
+func (v *OverridesView) fetchOverrides() tea.Msg {
+	log := logger.With("component", "overrides_view", "action", "fetch_overrides")
+	log.Info("Fetching overrides and enrollments")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+	overrides, err := client.GetOverrides()
+	if err != nil {
+		log.Error("Failed to fetch overrides", "error", err)
+		return errMsg(err)
+	}
+	enrollments, err := client.GetCourseEnrollments()
+	if err != nil {
+		log.Error("Failed to fetch enrollments", "error", err)
+		return errMsg(err)
+	}
+
+	names := map[int]string{}
+	for _, e := range enrollments {
+		names[e.User.ID] = e.User.Name
+	}
+	sort.SliceStable(overrides, func(i, j int) bool {
+		a, b := overrides[i].DueAt, overrides[j].DueAt
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+	return overridesLoadedMsg{overrides: overrides, names: names}
+}
+
+func (v *OverridesView) revoke(override api.AssignmentOverride) tea.Cmd {
+	return func() tea.Msg {
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+		err := client.DeleteOverride(override.AssignmentID, override.ID)
+		return overrideRevokedMsg{override: override, err: err}
+	}
+}
+
+// Message types
+type overridesLoadedMsg struct {
+	overrides []api.AssignmentOverride
+	names     map[int]string
+}
+
+type overrideRevokedMsg struct {
+	override api.AssignmentOverride
+	err      error
+}
@@ -0,0 +1,398 @@
+package views
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/grades"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const (
+	policyStudentPageSize = 10
+	policyWeightStep      = 5
+	policyAssignmentLimit = 8
+)
+
+type policyStudent struct {
+	name   string
+	scores grades.Scores
+}
+
+// GradingPolicyView shows the assignment groups with their weights and drop
+// rules, and previews every student's current score under new weights
+// before saving them.
+type GradingPolicyView struct {
+	groups        api.AssignmentGroups
+	students      []policyStudent
+	weights       map[int]float64
+	selected      int
+	studentOffset int
+	input         textinput.Model
+	inputMode     bool
+	confirmSave   bool
+	saving        bool
+	status        string
+	loaded        bool
+	err           error
+}
+
+func NewGradingPolicyView() *GradingPolicyView {
+	log := logger.With("component", "grading_policy_view")
+	log.Info("Creating new grading policy view")
+
+	ti := textinput.New()
+	ti.Placeholder = "weight"
+	ti.CharLimit = 6
+	ti.Width = 8
+
+	return &GradingPolicyView{
+		weights: map[int]float64{},
+		input:   ti,
+	}
+}
+
+func (v *GradingPolicyView) Init() tea.Cmd {
+	log := logger.With("component", "grading_policy_view")
+	log.Info("Initializing grading policy view")
+	return v.fetchPolicy
+}
+
+func (v *GradingPolicyView) CapturingInput() bool {
+	return v.inputMode
+}
+
+func (v *GradingPolicyView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "grading_policy_view")
+
+	if v.inputMode {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "enter":
+				v.inputMode = false
+				weight, err := strconv.ParseFloat(strings.TrimSpace(v.input.Value()), 64)
+				if err != nil || weight < 0 {
+					v.status = fmt.Sprintf("Invalid weight %q.", v.input.Value())
+					return v, nil
+				}
+				v.setWeight(weight)
+				return v, nil
+			case "esc":
+				v.inputMode = false
+				return v, nil
+			}
+		}
+		var cmd tea.Cmd
+		v.input, cmd = v.input.Update(msg)
+		return v, cmd
+	}
+
+	if v.confirmSave {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			v.confirmSave = false
+			if msg.String() == "y" {
+				log.Info("Saving assignment group weights", "changes", len(v.changes()))
+				v.saving = true
+				v.status = ""
+				return v, v.saveWeights(v.changes())
+			}
+			v.status = "Save cancelled."
+		}
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if v.saving {
+			return v, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.groups.Groups)-1 {
+				v.selected++
+			}
+		case "+", "=":
+			if v.loaded && len(v.groups.Groups) > 0 {
+				v.setWeight(v.weight(v.groups.Groups[v.selected]) + policyWeightStep)
+			}
+		case "-":
+			if v.loaded && len(v.groups.Groups) > 0 {
+				v.setWeight(math.Max(v.weight(v.groups.Groups[v.selected])-policyWeightStep, 0))
+			}
+		case "enter", "e":
+			if v.loaded && len(v.groups.Groups) > 0 {
+				v.inputMode = true
+				v.input.SetValue(strconv.FormatFloat(v.weight(v.groups.Groups[v.selected]), 'f', -1, 64))
+				v.input.Focus()
+				return v, textinput.Blink
+			}
+		case "u":
+			log.Info("Discarded weight changes", "changes", len(v.changes()))
+			v.weights = map[int]float64{}
+			v.status = ""
+		case "s":
+			if len(v.changes()) == 0 {
+				v.status = "No weight changes to save."
+				return v, nil
+			}
+			v.confirmSave = true
+		case "pgdown", "]":
+			if v.studentOffset+policyStudentPageSize < len(v.students) {
+				v.studentOffset += policyStudentPageSize
+			}
+		case "pgup", "[":
+			v.studentOffset = max(v.studentOffset-policyStudentPageSize, 0)
+		case "r":
+			v.loaded = false
+			v.err = nil
+			v.status = ""
+			return v, v.fetchPolicy
+		case "esc":
+			log.Info("Returning to home view")
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		}
+	case policyLoadedMsg:
+		log.Info("Received grading policy", "groups", len(msg.groups.Groups), "students", len(msg.students))
+		v.groups = msg.groups
+		v.students = msg.students
+		v.weights = map[int]float64{}
+		v.selected = min(v.selected, max(len(v.groups.Groups)-1, 0))
+		v.loaded = true
+	case policySavedMsg:
+		v.saving = false
+		if msg.failed > 0 {
+			v.status = fmt.Sprintf("%d of %d groups failed to save: %v", msg.failed, msg.total, msg.err)
+		} else {
+			v.status = fmt.Sprintf("Saved %d group weights.", msg.total)
+		}
+		return v, v.fetchPolicy
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *GradingPolicyView) weight(group api.AssignmentGroup) float64 {
+	if weight, ok := v.weights[group.ID]; ok {
+		return weight
+	}
+	return group.Weight
+}
+
+func (v *GradingPolicyView) setWeight(weight float64) {
+	group := v.groups.Groups[v.selected]
+	if weight == group.Weight {
+		delete(v.weights, group.ID)
+	} else {
+		v.weights[group.ID] = weight
+	}
+	v.status = ""
+}
+
+// changes are the edited weights that differ from Canvas, by group id.
+func (v *GradingPolicyView) changes() map[int]float64 {
+	changes := map[int]float64{}
+	for _, group := range v.groups.Groups {
+		if weight, ok := v.weights[group.ID]; ok && weight != group.Weight {
+			changes[group.ID] = weight
+		}
+	}
+	return changes
+}
+
+func (v *GradingPolicyView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+	if !v.loaded {
+		return "Loading grading policy..."
+	}
+
+	s := "Grading Policy\n==============\n\n"
+	if v.groups.Weighted {
+		s += "Final grade: weighted by assignment group\n\n"
+	} else {
+		s += "Final grade: total points. Weights only take effect once the course\n"
+		s += "is set to weight final grades by assignment group in Canvas.\n\n"
+	}
+
+	var total float64
+	for i, group := range v.groups.Groups {
+		cursor := " "
+		if i == v.selected {
+			cursor = ">"
+		}
+		weight := fmt.Sprintf("%g%%", group.Weight)
+		if changed, ok := v.weights[group.ID]; ok {
+			weight = fmt.Sprintf("%g%% → %g%%", group.Weight, changed)
+		}
+		total += v.weight(group)
+		s += fmt.Sprintf("%s %-28s %-16s %s\n", cursor, truncate(group.Name, 28), weight, dropRules(group))
+	}
+	s += fmt.Sprintf("  %-28s %g%%", "Total", total)
+	if len(v.groups.Groups) > 0 && total != 100 {
+		s += "  (weights don't add up to 100%)"
+	}
+	s += "\n\n"
+
+	if len(v.groups.Groups) > 0 {
+		group := v.groups.Groups[v.selected]
+		s += fmt.Sprintf("Assignments in %s (%d):\n", group.Name, len(group.Assignments))
+		for _, a := range group.Assignments[:min(len(group.Assignments), policyAssignmentLimit)] {
+			note := ""
+			if !grades.Counts(a) {
+				note = "  (doesn't count)"
+			}
+			s += fmt.Sprintf("  - %s (%g pts)%s\n", a.Name, a.PointsPossible, note)
+		}
+		if len(group.Assignments) > policyAssignmentLimit {
+			s += fmt.Sprintf("  ... and %d more\n", len(group.Assignments)-policyAssignmentLimit)
+		}
+		s += "\n"
+	}
+
+	s += v.previewView()
+
+	if v.inputMode {
+		s += fmt.Sprintf("Weight for %s (%%): %s\n(enter to set, esc to cancel)", v.groups.Groups[v.selected].Name, v.input.View())
+		return s
+	}
+	if v.confirmSave {
+		s += fmt.Sprintf("Save %d weight changes to Canvas? (y/n)", len(v.changes()))
+		return s
+	}
+	if v.saving {
+		return s + "Saving weights..."
+	}
+	if v.status != "" {
+		s += v.status + "\n\n"
+	}
+	s += "Keys: ↑/k ↓/j group, +/- adjust weight, enter/e set weight, u undo, s save\n"
+	s += "      pgup/pgdown students, r refresh\n"
+	s += "Press esc to go back, q to quit."
+	return s
+}
+
+// previewView lists every student's current score under the saved weights
+// and under the edited ones.
+func (v *GradingPolicyView) previewView() string {
+	if len(v.students) == 0 {
+		return "No students enrolled.\n\n"
+	}
+
+	preview := grades.Reweight(v.groups, v.weights)
+	s := fmt.Sprintf("  %-28s %8s %8s %8s\n", "Student", "Current", "New", "Change")
+	end := min(v.studentOffset+policyStudentPageSize, len(v.students))
+	for _, student := range v.students[v.studentOffset:end] {
+		before, ok := grades.Current(v.groups, student.scores)
+		if !ok {
+			s += fmt.Sprintf("  %-28s %8s %8s %8s\n", truncate(student.name, 28), "-", "-", "")
+			continue
+		}
+		after, _ := grades.Current(preview, student.scores)
+		change := ""
+		if delta := after - before; math.Abs(delta) >= 0.05 {
+			change = fmt.Sprintf("%+.1f", delta)
+		}
+		s += fmt.Sprintf("  %-28s %7.1f%% %7.1f%% %8s\n", truncate(student.name, 28), before, after, change)
+	}
+	s += fmt.Sprintf("\n  %d-%d of %d students\n\n", v.studentOffset+1, end, len(v.students))
+	return s
+}
+
+func dropRules(group api.AssignmentGroup) string {
+	var rules []string
+	if group.Rules.DropLowest > 0 {
+		rules = append(rules, fmt.Sprintf("drop lowest %d", group.Rules.DropLowest))
+	}
+	if group.Rules.DropHighest > 0 {
+		rules = append(rules, fmt.Sprintf("drop highest %d", group.Rules.DropHighest))
+	}
+	if len(group.Rules.NeverDrop) > 0 {
+		rules = append(rules, fmt.Sprintf("%d never dropped", len(group.Rules.NeverDrop)))
+	}
+	return strings.Join(rules, ", ")
+}
+
+func (v *GradingPolicyView) fetchPolicy() tea.Msg {
+	log := logger.With("component", "grading_policy_view", "action", "fetch_policy")
+	log.Info("Fetching assignment groups, enrollments and submissions")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+	groups, err := client.GetAssignmentGroups()
+	if err != nil {
+		log.Error("Failed to fetch assignment groups", "error", err)
+		return errMsg(err)
+	}
+	enrollments, err := client.GetCourseEnrollments()
+	if err != nil {
+		log.Error("Failed to fetch enrollments", "error", err)
+		return errMsg(err)
+	}
+	submissions, err := client.GetSubmissions()
+	if err != nil {
+		log.Error("Failed to fetch submissions", "error", err)
+		return errMsg(err)
+	}
+
+	var students []policyStudent
+	for _, e := range StudentEnrollments(enrollments) {
+		students = append(students, policyStudent{
+			name:   e.User.Name,
+			scores: grades.StudentScores(submissions, e.User.ID),
+		})
+	}
+	sort.Slice(students, func(i, j int) bool {
+		return strings.ToLower(students[i].name) < strings.ToLower(students[j].name)
+	})
+	return policyLoadedMsg{groups: groups, students: students}
+}
+
+func (v *GradingPolicyView) saveWeights(changes map[int]float64) tea.Cmd {
+	return func() tea.Msg {
+		log := logger.With("component", "grading_policy_view", "action", "save_weights")
+
+		port := os.Getenv("PORT")
+		courseId := os.Getenv("COURSE_ID")
+		client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+		result := policySavedMsg{total: len(changes)}
+		for groupID, weight := range changes {
+			if err := client.UpdateAssignmentGroup(groupID, api.UpdateAssignmentGroupRequest{Weight: weight}); err != nil {
+				log.Error("Failed to update assignment group", "error", err, "group_id", groupID)
+				result.failed++
+				result.err = err
+			}
+		}
+		return result
+	}
+}
+
+// Message types
+type policyLoadedMsg struct {
+	groups   api.AssignmentGroups
+	students []policyStudent
+}
+
+type policySavedMsg struct {
+	total  int
+	failed int
+	err    error
+}
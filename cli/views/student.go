@@ -0,0 +1,171 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+type StudentAction int
+
+const (
+	ActionMessageStudent StudentAction = iota
+	ActionWhatIf
+	ActionExtendDeadline
+)
+
+var studentActions = []struct {
+	name string
+	key  StudentAction
+}{
+	{"Message", ActionMessageStudent},
+	{"What-If Grades", ActionWhatIf},
+	{"Extend Deadline", ActionExtendDeadline},
+}
+
+type StudentView struct {
+	enrollment api.Enrollment
+	missing    []api.Assignment
+	loaded     bool
+	selected   int
+	err        error
+}
+
+func NewStudentView(enrollment api.Enrollment) *StudentView {
+	log := logger.With("component", "student_view", "student_name", enrollment.User.Name)
+	log.Info("Creating new student view")
+	return &StudentView{
+		enrollment: enrollment,
+	}
+}
+
+func (v *StudentView) Init() tea.Cmd {
+	log := logger.With("component", "student_view", "student_name", v.enrollment.User.Name)
+	log.Info("Initializing student view")
+	return v.fetchMissing
+}
+
+func (v *StudentView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "student_view", "student_name", v.enrollment.User.Name)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(studentActions)-1 {
+				v.selected++
+			}
+		case "enter":
+			action := studentActions[v.selected].key
+			log.Info("Selected action", "action", studentActions[v.selected].name)
+
+			switch action {
+			case ActionMessageStudent:
+				enrollment := v.enrollment
+				return v, func() tea.Msg {
+					return ComposeMessageMsg{
+						Recipients: []api.Enrollment{enrollment},
+						Back:       EnrollmentSelectedMsg{Enrollment: enrollment},
+					}
+				}
+			case ActionWhatIf:
+				enrollment := v.enrollment
+				return v, func() tea.Msg {
+					return WhatIfSelectedMsg{Enrollment: enrollment}
+				}
+			case ActionExtendDeadline:
+				enrollment := v.enrollment
+				return v, func() tea.Msg {
+					return ExtendDeadlineMsg{Enrollment: enrollment}
+				}
+			}
+		case "esc":
+			log.Info("Returning to enrollments view")
+			return v, func() tea.Msg {
+				return EnrollmentsView{}
+			}
+		}
+	case missingAssignmentsMsg:
+		log.Info("Received missing assignments", "count", len(msg))
+		v.missing = msg
+		v.loaded = true
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *StudentView) View() string {
+	e := v.enrollment
+	s := fmt.Sprintf("Student: %s\n", e.User.Name)
+	s += strings.Repeat("=", len("Student: ")+len(e.User.Name)) + "\n\n"
+	s += fmt.Sprintf("  ID:            %d\n", e.User.ID)
+	s += fmt.Sprintf("  State:         %s\n", e.State)
+	s += fmt.Sprintf("  Current score: %.1f%%\n", e.Grades.Score)
+	if e.Grades.Url != "" {
+		s += fmt.Sprintf("  Grades:        %s\n", e.Grades.Url)
+	}
+	s += "\n"
+
+	switch {
+	case v.err != nil:
+		s += fmt.Sprintf("Error: %v\n\n", v.err)
+	case !v.loaded:
+		s += "Loading missing work...\n\n"
+	case len(v.missing) == 0:
+		s += "No missing work.\n\n"
+	default:
+		s += fmt.Sprintf("Missing work (%d):\n", len(v.missing))
+		for _, assignment := range v.missing {
+			due := ""
+			if assignment.DueAt != nil {
+				due = " (due " + assignment.DueAt.Local().Format("Jan 2") + ")"
+			}
+			s += fmt.Sprintf("  - %s%s\n", assignment.Name, due)
+		}
+		s += "\n"
+	}
+
+	s += "Select an action:\n\n"
+	for i, action := range studentActions {
+		cursor := " "
+		if v.selected == i {
+			cursor = ">"
+		}
+		s += fmt.Sprintf("%s %s\n", cursor, action.name)
+	}
+	s += "\nPress esc to go back, q to quit."
+	return s
+}
+
+func (v *StudentView) fetchMissing() tea.Msg {
+	log := logger.With(
+		"component", "student_view",
+		"action", "fetch_missing",
+		"student_name", v.enrollment.User.Name,
+	)
+	log.Info("Fetching missing assignments")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+	missing, err := client.GetMissingAssignments(v.enrollment.User.ID)
+	if err != nil {
+		log.Error("Failed to fetch missing assignments", "error", err)
+		return errMsg(err)
+	}
+	return missingAssignmentsMsg(missing)
+}
+
+// Message types
+type missingAssignmentsMsg []api.Assignment
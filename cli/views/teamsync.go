@@ -0,0 +1,230 @@
+package views
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/roster"
+)
+
+const teamSyncPageSize = 15
+
+// TeamSyncView previews the invitations and removals that bring the cohort
+// GitHub team in line with the Canvas roster, and applies them on request.
+type TeamSyncView struct {
+	source   string
+	team     api.GitHubTeam
+	plan     roster.Plan
+	selected int
+	loading  string
+	confirm  bool
+	results  []string
+	err      error
+}
+
+func NewTeamSyncView() *TeamSyncView {
+	log := logger.With("component", "team_sync_view")
+	log.Info("Creating new team sync view")
+
+	// Prefer the local mapping once someone has started one.
+	source := roster.SourceCanvas
+	if _, err := os.Stat(roster.DefaultPath()); err == nil {
+		source = roster.SourceFile
+	}
+	return &TeamSyncView{source: source, loading: "Comparing roster with GitHub team"}
+}
+
+func (v *TeamSyncView) Init() tea.Cmd {
+	return v.buildPlan
+}
+
+func (v *TeamSyncView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "team_sync_view")
+
+	if v.confirm {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "y":
+				v.confirm = false
+				v.loading = fmt.Sprintf("Applying %d changes", len(v.plan.Changes))
+				return v, v.apply
+			case "n", "esc":
+				v.confirm = false
+			}
+		}
+		return v, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if v.loading != "" {
+			return v, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.plan.Changes)-1 {
+				v.selected++
+			}
+		case "s":
+			if v.source == roster.SourceFile {
+				v.source = roster.SourceCanvas
+			} else {
+				v.source = roster.SourceFile
+			}
+			v.loading = "Comparing roster with GitHub team"
+			v.results = nil
+			return v, v.buildPlan
+		case "r":
+			v.loading = "Comparing roster with GitHub team"
+			v.results = nil
+			return v, v.buildPlan
+		case "y":
+			if len(v.plan.Changes) > 0 {
+				v.confirm = true
+			}
+		case "esc":
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		}
+	case teamSyncPlanMsg:
+		log.Info("Built team sync plan", "changes", len(msg.plan.Changes), "unmapped", len(msg.plan.Unmapped))
+		v.team = msg.team
+		v.plan = msg.plan
+		v.selected = 0
+		v.loading = ""
+		v.err = nil
+	case teamSyncAppliedMsg:
+		log.Info("Applied team sync", "results", len(msg))
+		v.results = msg
+		v.loading = "Comparing roster with GitHub team"
+		return v, v.buildPlan
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.loading = ""
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *TeamSyncView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress s to switch username source (now %s), esc to go back, q to quit.", v.err, v.source)
+	}
+
+	if v.loading != "" {
+		return v.loading + "..."
+	}
+
+	invites, removals := v.plan.Count()
+	s := fmt.Sprintf("Sync roster to GitHub team %s/%s (usernames from %s)\n", v.team.Organization, github.TeamSlug(v.team.Team), v.sourceLabel())
+	s += fmt.Sprintf("%d in sync, %d to invite, %d to remove, %d without a GitHub username\n\n",
+		len(v.plan.InSync), invites, removals, len(v.plan.Unmapped))
+
+	if len(v.plan.Changes) == 0 {
+		s += "The team matches the roster.\n"
+	}
+	start, end := pageBounds(v.selected, len(v.plan.Changes), teamSyncPageSize)
+	for i := start; i < end; i++ {
+		change := v.plan.Changes[i]
+		cursor := " "
+		if v.selected == i {
+			cursor = ">"
+		}
+		student := change.Student
+		if student == "" {
+			student = "(not on the roster)"
+		}
+		s += fmt.Sprintf("%s %-7s %-25s %s\n", cursor, change.Action, truncate(change.Username, 25), student)
+	}
+
+	if len(v.plan.Unmapped) > 0 {
+		s += fmt.Sprintf("\nNo GitHub username: %s\n", firstN(v.plan.Unmapped, 5))
+	}
+	if len(v.plan.Held) > 0 {
+		s += fmt.Sprintf("Not removing %s until they have one, since these may be their accounts.\n", firstN(v.plan.Held, 5))
+	}
+
+	if len(v.results) > 0 {
+		s += "\nLast sync:\n"
+		for _, result := range v.results {
+			s += "  " + result + "\n"
+		}
+	}
+
+	if v.confirm {
+		return s + fmt.Sprintf("\nInvite %d and remove %d members? (y/n)", invites, removals)
+	}
+	return s + "\nPress y to apply, s to switch username source, r to refresh, esc to go back, q to quit."
+}
+
+func (v *TeamSyncView) sourceLabel() string {
+	if v.source == roster.SourceFile {
+		return roster.DefaultPath()
+	}
+	return fmt.Sprintf("the %q gradebook column", roster.DefaultColumn)
+}
+
+func (v *TeamSyncView) buildPlan() tea.Msg {
+	log := logger.With("component", "team_sync_view", "action", "build_plan", "source", v.source)
+
+	gh, err := github.NewClientFromEnv()
+	if err != nil {
+		return errMsg(err)
+	}
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+
+	team, err := client.GetGitHubTeam()
+	if err != nil {
+		log.Error("Failed to fetch GitHub team", "error", err)
+		return errMsg(err)
+	}
+	usernames, err := roster.LoadUsernames(client, v.source, roster.DefaultPath(), roster.DefaultColumn)
+	if err != nil {
+		log.Error("Failed to load GitHub usernames", "error", err)
+		return errMsg(err)
+	}
+	plan, err := roster.BuildPlan(client, gh, team, usernames)
+	if err != nil {
+		log.Error("Failed to build plan", "error", err)
+		return errMsg(err)
+	}
+	return teamSyncPlanMsg{team: team, plan: plan}
+}
+
+func (v *TeamSyncView) apply() tea.Msg {
+	gh, err := github.NewClientFromEnv()
+	if err != nil {
+		return errMsg(err)
+	}
+
+	var results []string
+	roster.Apply(gh, v.team, v.plan.Changes, func(change roster.Change, err error) {
+		if err != nil {
+			results = append(results, fmt.Sprintf("failed to %s %s: %v", change.Action, change.Username, err))
+			return
+		}
+		results = append(results, fmt.Sprintf("%s %s", change.Action, change.Username))
+	})
+	return teamSyncAppliedMsg(results)
+}
+
+// Message types
+type teamSyncPlanMsg struct {
+	team api.GitHubTeam
+	plan roster.Plan
+}
+
+type teamSyncAppliedMsg []string
@@ -0,0 +1,188 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+// toGradePageSize is how many submissions are shown at once.
+const toGradePageSize = 20
+
+type ToGradeView struct {
+	submissions []api.Submission
+	selected    int
+	loaded      bool
+	err         error
+}
+
+func NewToGradeView() *ToGradeView {
+	log := logger.With("component", "to_grade_view")
+	log.Info("Creating new to grade view")
+	return &ToGradeView{
+		submissions: []api.Submission{},
+		selected:    0,
+	}
+}
+
+func (v *ToGradeView) Init() tea.Cmd {
+	log := logger.With("component", "to_grade_view")
+	log.Info("Initializing to grade view")
+	return v.fetchSubmissions
+}
+
+func (v *ToGradeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "to_grade_view")
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.submissions)-1 {
+				v.selected++
+			}
+		case "enter":
+			if len(v.submissions) > 0 {
+				selectedSubmission := v.submissions[v.selected]
+				log.Info("Selected submission",
+					"submission_id", selectedSubmission.ID,
+					"student_name", selectedSubmission.User.Name)
+				return v, func() tea.Msg {
+					return SubmissionSelectedMsg{Submission: selectedSubmission}
+				}
+			}
+		case "r":
+			log.Info("Refreshing submissions")
+			v.loaded = false
+			v.selected = 0
+			return v, v.fetchSubmissions
+		case "esc":
+			log.Info("Returning to home view")
+			return v, func() tea.Msg {
+				return HomeView{}
+			}
+		}
+	case submissionsMsg:
+		log.Info("Received submissions", "count", len(msg))
+		v.submissions = msg
+		v.loaded = true
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *ToGradeView) View() string {
+	if v.err != nil {
+		return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+	}
+
+	if !v.loaded {
+		return "Loading submissions...\n\nPress esc to go back, q to quit."
+	}
+
+	if len(v.submissions) == 0 {
+		return "To Grade\n\nNothing to grade. Nice work!\n\nPress r to refresh, esc to go back, q to quit."
+	}
+
+	s := fmt.Sprintf("To Grade (%d submissions, oldest first)\n\n", len(v.submissions))
+	s += fmt.Sprintf("  %-28s %-36s %-17s %-5s\n", "Student", "Assignment", "Submitted", "Late")
+	s += strings.Repeat("─", 90) + "\n"
+
+	start, end := pageBounds(v.selected, len(v.submissions), toGradePageSize)
+	for i := start; i < end; i++ {
+		cursor := " "
+		if v.selected == i {
+			cursor = ">"
+		}
+		s += v.formatSubmissionRow(cursor, v.submissions[i])
+	}
+
+	s += strings.Repeat("─", 90) + "\n"
+	s += fmt.Sprintf("Showing %d-%d of %d\n", start+1, end, len(v.submissions))
+	s += "\nNavigation: ↑/k (up), ↓/j (down), Enter (grade), r (refresh), Esc (back), q (quit)"
+	return s
+}
+
+func (v *ToGradeView) formatSubmissionRow(cursor string, submission api.Submission) string {
+	submitted := "-"
+	if submission.SubmittedAt != nil {
+		submitted = submission.SubmittedAt.Local().Format("2006-01-02 15:04")
+	}
+
+	late := ""
+	if submission.Late {
+		late = "LATE"
+	}
+
+	return fmt.Sprintf("%s %-28s %-36s %-17s %-5s\n",
+		cursor,
+		truncate(submission.User.Name, 28),
+		truncate(submission.Assignment.Name, 36),
+		submitted,
+		late,
+	)
+}
+
+func (v *ToGradeView) fetchSubmissions() tea.Msg {
+	log := logger.With(
+		"component", "to_grade_view",
+		"action", "fetch_submissions",
+	)
+	log.Info("Fetching ungraded submissions")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+	submissions, err := client.GetSubmissions("submitted", "pending_review")
+	if err != nil {
+		log.Error("Failed to fetch submissions", "error", err)
+		return errMsg(err)
+	}
+
+	// Oldest first so nothing waits longer than it has to
+	sort.SliceStable(submissions, func(i, j int) bool {
+		a, b := submissions[i].SubmittedAt, submissions[j].SubmittedAt
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.Before(*b)
+	})
+
+	return submissionsMsg(submissions)
+}
+
+// pageBounds returns the [start, end) window of size rows that keeps the
+// selected row visible.
+func pageBounds(selected, total, size int) (int, int) {
+	start := 0
+	if selected >= size {
+		start = selected - size + 1
+	}
+	end := start + size
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func truncate(s string, width int) string {
+	if len(s) > width {
+		return s[:width-3] + "..."
+	}
+	return s
+}
+
+// Message types
+type submissionsMsg []api.Submission
@@ -0,0 +1,301 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/grades"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/logger"
+)
+
+const whatIfPageSize = 15
+
+type whatIfInput int
+
+const (
+	whatIfInputNone whatIfInput = iota
+	whatIfInputScore
+	whatIfInputPassing
+)
+
+type whatIfRow struct {
+	group      string
+	assignment api.Assignment
+}
+
+// WhatIfView lets an instructor try hypothetical scores for one student and
+// see the projected final score and what's needed to pass.
+type WhatIfView struct {
+	enrollment   api.Enrollment
+	groups       api.AssignmentGroups
+	scores       grades.Scores
+	hypothetical map[int]float64
+	rows         []whatIfRow
+	passing      float64
+	input        textinput.Model
+	inputMode    whatIfInput
+	selected     int
+	loaded       bool
+	err          error
+}
+
+type WhatIfSelectedMsg struct {
+	Enrollment api.Enrollment
+}
+
+func NewWhatIfView(enrollment api.Enrollment) *WhatIfView {
+	log := logger.With("component", "what_if_view", "student_name", enrollment.User.Name)
+	log.Info("Creating new what-if view")
+
+	ti := textinput.New()
+	ti.CharLimit = 10
+	ti.Width = 10
+
+	return &WhatIfView{
+		enrollment:   enrollment,
+		hypothetical: map[int]float64{},
+		passing:      grades.PassingScore,
+		input:        ti,
+	}
+}
+
+func (v *WhatIfView) Init() tea.Cmd {
+	log := logger.With("component", "what_if_view", "student_name", v.enrollment.User.Name)
+	log.Info("Initializing what-if view")
+	return v.fetchGrades
+}
+
+func (v *WhatIfView) CapturingInput() bool {
+	return v.inputMode != whatIfInputNone
+}
+
+func (v *WhatIfView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	log := logger.With("component", "what_if_view", "student_name", v.enrollment.User.Name)
+
+	if v.inputMode != whatIfInputNone {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "enter":
+				value, err := strconv.ParseFloat(strings.TrimSpace(v.input.Value()), 64)
+				if err != nil || value < 0 {
+					v.err = fmt.Errorf("invalid number %q", v.input.Value())
+					v.inputMode = whatIfInputNone
+					return v, nil
+				}
+				if v.inputMode == whatIfInputPassing {
+					log.Info("Set passing score", "passing", value)
+					v.passing = value
+				} else {
+					assignment := v.rows[v.selected].assignment
+					log.Info("Set what-if score", "assignment_id", assignment.ID, "score", value)
+					v.hypothetical[assignment.ID] = value
+				}
+				v.inputMode = whatIfInputNone
+				return v, nil
+			case "esc":
+				v.inputMode = whatIfInputNone
+				return v, nil
+			}
+		}
+		var cmd tea.Cmd
+		v.input, cmd = v.input.Update(msg)
+		return v, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if v.selected > 0 {
+				v.selected--
+			}
+		case "down", "j":
+			if v.selected < len(v.rows)-1 {
+				v.selected++
+			}
+		case "enter", "e":
+			if v.loaded && len(v.rows) > 0 {
+				v.err = nil
+				v.inputMode = whatIfInputScore
+				v.input.Placeholder = fmt.Sprintf("0-%g", v.rows[v.selected].assignment.PointsPossible)
+				v.input.SetValue("")
+				v.input.Focus()
+				return v, textinput.Blink
+			}
+		case "p":
+			v.err = nil
+			v.inputMode = whatIfInputPassing
+			v.input.Placeholder = "percent"
+			v.input.SetValue(strconv.FormatFloat(v.passing, 'f', -1, 64))
+			v.input.Focus()
+			return v, textinput.Blink
+		case "x":
+			if len(v.rows) > 0 {
+				delete(v.hypothetical, v.rows[v.selected].assignment.ID)
+			}
+		case "c":
+			log.Info("Cleared what-if scores", "count", len(v.hypothetical))
+			v.hypothetical = map[int]float64{}
+		case "esc":
+			log.Info("Returning to student view")
+			enrollment := v.enrollment
+			return v, func() tea.Msg {
+				return EnrollmentSelectedMsg{Enrollment: enrollment}
+			}
+		}
+	case whatIfLoadedMsg:
+		log.Info("Received assignment groups and scores", "groups", len(msg.groups.Groups))
+		v.groups = msg.groups
+		v.scores = msg.scores
+		v.rows = nil
+		for _, group := range msg.groups.Groups {
+			label := group.Name
+			if msg.groups.Weighted {
+				label = fmt.Sprintf("%s (%g%%)", group.Name, group.Weight)
+			}
+			for _, a := range group.Assignments {
+				if grades.Counts(a) {
+					v.rows = append(v.rows, whatIfRow{group: label, assignment: a})
+				}
+			}
+		}
+		v.loaded = true
+	case errMsg:
+		log.Error("Error occurred", "error", msg)
+		v.err = msg
+	}
+
+	return v, nil
+}
+
+func (v *WhatIfView) View() string {
+	title := fmt.Sprintf("What-If Grades: %s", v.enrollment.User.Name)
+	s := title + "\n" + strings.Repeat("=", len(title)) + "\n\n"
+
+	if !v.loaded {
+		if v.err != nil {
+			return fmt.Sprintf("Error: %v\n\nPress esc to go back, q to quit.", v.err)
+		}
+		return s + "Loading grades..."
+	}
+
+	scores := v.scores.With(v.hypothetical)
+	if current, ok := grades.Current(v.groups, v.scores); ok {
+		s += fmt.Sprintf("  Current score:   %5.1f%%  (graded work only)\n", current)
+	} else {
+		s += "  Current score:       -   (nothing graded yet)\n"
+	}
+	s += fmt.Sprintf("  Projected final: %5.1f%%  (what-if scores, remaining work at zero)\n", grades.Final(v.groups, scores))
+
+	remaining := grades.Remaining(v.groups, scores)
+	share, reachable := grades.Needed(v.groups, scores, v.passing)
+	switch {
+	case len(remaining) == 0 && reachable:
+		s += fmt.Sprintf("  To pass (%g%%):   passing with nothing left to grade\n", v.passing)
+	case len(remaining) == 0:
+		s += fmt.Sprintf("  To pass (%g%%):   not passing with nothing left to grade\n", v.passing)
+	case !reachable:
+		s += fmt.Sprintf("  To pass (%g%%):   out of reach even with full marks on everything left\n", v.passing)
+	case share == 0:
+		s += fmt.Sprintf("  To pass (%g%%):   already passing, even with zero on what's left\n", v.passing)
+	default:
+		s += fmt.Sprintf("  To pass (%g%%):   needs %.0f%% on each remaining assignment (%d left)\n", v.passing, share*100, len(remaining))
+	}
+	s += "\n"
+
+	if len(v.rows) == 0 {
+		s += "No assignments count toward the grade.\n\n"
+	} else {
+		s += fmt.Sprintf("  %-20s %-30s %-14s %s\n", "Group", "Assignment", "Score", "Needed")
+		start, end := pageBounds(v.selected, len(v.rows), whatIfPageSize)
+		for i := start; i < end; i++ {
+			row := v.rows[i]
+			a := row.assignment
+
+			cursor := " "
+			if i == v.selected {
+				cursor = ">"
+			}
+			group := row.group
+			if i > start && v.rows[i-1].group == row.group {
+				group = ""
+			}
+
+			score := fmt.Sprintf("- / %g", a.PointsPossible)
+			needed := ""
+			switch {
+			case v.scores.Excused[a.ID]:
+				score = "excused"
+			case hasKey(v.hypothetical, a.ID):
+				score = fmt.Sprintf("*%g / %g", v.hypothetical[a.ID], a.PointsPossible)
+			case hasKey(v.scores.Points, a.ID):
+				score = fmt.Sprintf("%g / %g", v.scores.Points[a.ID], a.PointsPossible)
+			case reachable:
+				needed = fmt.Sprintf("%.1f", share*a.PointsPossible)
+			default:
+				needed = "-"
+			}
+			s += fmt.Sprintf("%s %-20s %-30s %-14s %s\n", cursor, truncate(group, 20), truncate(a.Name, 30), score, needed)
+		}
+		s += fmt.Sprintf("\n  %d-%d of %d  (* what-if score)\n\n", start+1, end, len(v.rows))
+	}
+
+	switch v.inputMode {
+	case whatIfInputScore:
+		s += fmt.Sprintf("What-if score for %s: %s\n(enter to set, esc to cancel)", v.rows[v.selected].assignment.Name, v.input.View())
+		return s
+	case whatIfInputPassing:
+		s += fmt.Sprintf("Passing score (%%): %s\n(enter to set, esc to cancel)", v.input.View())
+		return s
+	}
+
+	if v.err != nil {
+		s += fmt.Sprintf("Error: %v\n\n", v.err)
+	}
+	s += "Keys: ↑/k ↓/j move, enter/e what-if score, x clear score, c clear all, p passing score\n"
+	s += "Press esc to go back, q to quit."
+	return s
+}
+
+func hasKey(m map[int]float64, key int) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func (v *WhatIfView) fetchGrades() tea.Msg {
+	log := logger.With(
+		"component", "what_if_view",
+		"action", "fetch_grades",
+		"student_name", v.enrollment.User.Name,
+	)
+	log.Info("Fetching assignment groups and submissions")
+
+	port := os.Getenv("PORT")
+	courseId := os.Getenv("COURSE_ID")
+	client := api.NewClient(fmt.Sprintf("http://localhost:%s", port), courseId)
+	groups, err := client.GetAssignmentGroups()
+	if err != nil {
+		log.Error("Failed to fetch assignment groups", "error", err)
+		return errMsg(err)
+	}
+	submissions, err := client.GetSubmissions()
+	if err != nil {
+		log.Error("Failed to fetch submissions", "error", err)
+		return errMsg(err)
+	}
+	return whatIfLoadedMsg{
+		groups: groups,
+		scores: grades.StudentScores(submissions, v.enrollment.User.ID),
+	}
+}
+
+// Message types
+type whatIfLoadedMsg struct {
+	groups api.AssignmentGroups
+	scores grades.Scores
+}
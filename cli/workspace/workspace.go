@@ -0,0 +1,258 @@
+// Package workspace checks out student repositories for an assignment into a
+// local directory tree, workspace/<assignment>/<student>.
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/api"
+	"github.com/wolfy/code/fullstack/canvasInstructor/cli/github"
+)
+
+const (
+	DefaultRoot        = "workspace"
+	DefaultConcurrency = 4
+	DefaultTimeout     = 2 * time.Minute
+)
+
+// Target is one student's repository and where it is checked out.
+type Target struct {
+	Student string `json:"student"`
+	UserID  int    `json:"user_id"`
+	RepoURL string `json:"repo_url"`
+	// Source says how the repository was found: "submission url",
+	// "submission body" or "fork".
+	Source string `json:"source"`
+	Dir    string `json:"dir"`
+}
+
+type Result struct {
+	Target
+	Action     string     `json:"action,omitempty"`
+	LastCommit *time.Time `json:"last_commit,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+var namePattern = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// DirName turns a display name into a directory name, e.g. "Ada Lovelace"
+// becomes "ada-lovelace".
+func DirName(name string) string {
+	name = strings.Trim(namePattern.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
+
+// studentDirs maps each submitting user to their directory under dir. Students
+// who share a display name get their user id appended so they never share a
+// checkout.
+func studentDirs(dir string, submissions []api.Submission) map[int]string {
+	count := map[string]int{}
+	for _, s := range submissions {
+		count[DirName(studentName(s))]++
+	}
+
+	dirs := map[int]string{}
+	for _, s := range submissions {
+		name := DirName(studentName(s))
+		if count[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, s.UserID)
+		}
+		dirs[s.UserID] = filepath.Join(dir, name)
+	}
+	return dirs
+}
+
+// AssignmentDir is where the assignment's repositories are checked out.
+func AssignmentDir(root string, assignment api.Assignment) string {
+	return filepath.Join(root, DirName(assignment.Name))
+}
+
+// Resolve finds a repository for each submission. It uses a GitHub link in
+// the submitted URL, then one in the submission body, and finally, when the
+// assignment links a starter repository and the student's GitHub username is
+// known, the student's fork of it. Students without a repository are
+// returned as unresolved.
+func Resolve(root string, assignment api.Assignment, submissions []api.Submission, usernames map[int]string) (targets []Target, unresolved []string) {
+	var starter *github.Repo
+	if repos := github.FindRepos(assignment.Description); len(repos) > 0 {
+		starter = &repos[0]
+	}
+
+	dirs := studentDirs(AssignmentDir(root, assignment), submissions)
+	for _, s := range submissions {
+		name := studentName(s)
+		target := Target{Student: name, UserID: s.UserID, Dir: dirs[s.UserID]}
+		if repos := github.FindRepos(s.URL); len(repos) > 0 {
+			target.RepoURL, target.Source = repos[0].URL(), "submission url"
+		} else if repos := github.FindRepos(s.Body); len(repos) > 0 {
+			target.RepoURL, target.Source = repos[0].URL(), "submission body"
+		} else if username := usernames[s.UserID]; starter != nil && username != "" {
+			fork := github.Repo{Owner: username, Name: starter.Name}
+			target.RepoURL, target.Source = fork.URL(), "fork"
+		} else {
+			unresolved = append(unresolved, name)
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Student < targets[j].Student })
+	sort.Strings(unresolved)
+	return targets, unresolved
+}
+
+// CheckedOut returns a target for each submitting student whose repository
+// is already in the workspace, however it was found.
+func CheckedOut(root string, assignment api.Assignment, submissions []api.Submission) []Target {
+	dirs := studentDirs(AssignmentDir(root, assignment), submissions)
+	var targets []Target
+	for _, s := range submissions {
+		target := Target{Student: studentName(s), UserID: s.UserID, Dir: dirs[s.UserID]}
+		if _, err := os.Stat(filepath.Join(target.Dir, ".git")); err == nil {
+			targets = append(targets, target)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Student < targets[j].Student })
+	return targets
+}
+
+func studentName(s api.Submission) string {
+	if s.User.Name == "" {
+		return fmt.Sprintf("user-%d", s.UserID)
+	}
+	return s.User.Name
+}
+
+// Sync clones each target that isn't checked out yet and fetches the rest,
+// resetting their checkout to the remote's default branch and removing
+// anything earlier runs left in it. It works on at most concurrency targets
+// at a time, each bounded by timeout. Results are in the same order as
+// targets.
+func Sync(ctx context.Context, targets []Target, concurrency int, timeout time.Duration) []Result {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	results := make([]Result, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = syncTarget(ctx, target, timeout)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func syncTarget(ctx context.Context, target Target, timeout time.Duration) Result {
+	result := Result{Target: target}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var err error
+	if _, statErr := os.Stat(filepath.Join(target.Dir, ".git")); statErr == nil {
+		result.Action = "fetched"
+		err = update(ctx, target.Dir)
+	} else {
+		result.Action = "cloned"
+		if err = os.MkdirAll(filepath.Dir(target.Dir), 0755); err == nil {
+			_, err = git(ctx, "", "clone", "--quiet", target.RepoURL, target.Dir)
+		}
+	}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		result.Error = err.Error()
+		return result
+	}
+
+	// The newest commit on any branch, since students don't always push to
+	// the default one.
+	out, err := git(ctx, target.Dir, "log", "--all", "-1", "--format=%cI")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if out == "" {
+		return result
+	}
+	if committed, err := time.Parse(time.RFC3339, out); err == nil {
+		result.LastCommit = &committed
+	}
+	return result
+}
+
+// update fetches an existing checkout, resets it to the remote's default
+// branch and removes untracked and ignored files, so tests and comparisons
+// see exactly what the student last pushed.
+func update(ctx context.Context, dir string) error {
+	if _, err := git(ctx, dir, "fetch", "--all", "--prune"); err != nil {
+		return err
+	}
+	// The default branch may have been renamed since the clone
+	if _, err := git(ctx, dir, "remote", "set-head", "origin", "--auto"); err != nil {
+		return err
+	}
+	ref, err := git(ctx, dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		return err
+	}
+	branch := strings.TrimPrefix(ref, "origin/")
+	if _, err := git(ctx, dir, "checkout", "--quiet", "--force", "-B", branch, ref); err != nil {
+		return err
+	}
+	// Build output and reports from earlier runs would otherwise be graded
+	_, err = git(ctx, dir, "clean", "--quiet", "-fdx")
+	return err
+}
+
+// git runs a git command without ever prompting for credentials, which
+// would hang on private repositories.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(out))
+		if message == "" {
+			return "", fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], firstError(message))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// firstError picks the line of git output that explains the failure.
+func firstError(output string) string {
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "fatal: ") || strings.HasPrefix(line, "error: ") {
+			return strings.TrimPrefix(strings.TrimPrefix(line, "fatal: "), "error: ")
+		}
+	}
+	return lines[0]
+}